@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+const addUsage = "Usage: doitdoit add <title> [--date YYYY-MM-DD | --in N | --future] [--notes TEXT] [--repeat RULE] [--deadline DATE]"
+
+func runAdd(args []string, e env, out, errOut io.Writer) int {
+	fs := newFlagSet("add", addUsage, errOut)
+	date := fs.String("date", "", "schedule for a date (YYYY-MM-DD or MM-DD)")
+	in := fs.Int("in", 0, "schedule this many days from today")
+	future := fs.Bool("future", false, "add to Future without a date")
+	notes := fs.String("notes", "", "attach notes to the task")
+	repeat := fs.String("repeat", "", "repeat rule: daily, weekdays, every N days, weekly on mon,fri, monthly on N")
+	deadline := fs.String("deadline", "", "must be done by this day (today, tomorrow, +N, YYYY-MM-DD, or MM-DD)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return flagExitCode(err)
+	}
+
+	title := joinTitle(positional)
+	if title == "" {
+		fmt.Fprintln(errOut, addUsage)
+		return 1
+	}
+	set := 0
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "date" || f.Name == "in" || f.Name == "future" {
+			set++
+		}
+	})
+	if set > 1 {
+		fmt.Fprintln(errOut, "Choose only one of --date, --in, or --future.")
+		return 1
+	}
+	if *in < 0 {
+		fmt.Fprintln(errOut, "--in must be zero or a positive number of days.")
+		return 1
+	}
+
+	today := e.cal.Today()
+	targetDate := ""
+	switch {
+	case *date != "":
+		targetDate, err = model.NormalizeDueDate(*date, today)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+			return 1
+		}
+	case *in > 0:
+		targetDate = today.AddDate(0, 0, *in).Format("2006-01-02")
+	}
+
+	rule := ""
+	if *repeat != "" {
+		parsed, err := model.ParseRecurrence(*repeat)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+			return 1
+		}
+		rule = parsed.String()
+	}
+
+	deadlineDate := ""
+	if *deadline != "" {
+		deadlineDate, err = resolveDeadline(*deadline, today)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	task := model.NewTask(title, e.cal.Now())
+	task.Notes = strings.TrimRight(*notes, " \t\r\n")
+	task.Repeat = rule
+	task.Deadline = deadlineDate
+	key := today.Format("2006-01-02")
+	switch {
+	case *future:
+		key = model.FutureKey
+	case targetDate != "":
+		// Headless commands have no viewport, so anything after today waits
+		// in Future until the TUI or `list` brings its day into view.
+		key, task.DueDate, err = model.ScheduleKey(targetDate, today, today)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+			return 1
+		}
+	}
+	err = model.Update(e.path, e.retentionDays, today, e.save, func(data model.TodoData) (bool, error) {
+		data.Add(key, task)
+		return true, nil
+	})
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "Added %q to %s (id %s)\n", task.Title, describePlacement(key, task.DueDate, today), task.ID)
+	return 0
+}
+
+// describePlacement names a task's list for confirmation messages, calling
+// today's list Today.
+func describePlacement(key, dueDate string, today time.Time) string {
+	switch {
+	case key == model.FutureKey && dueDate != "":
+		return "Future (due " + dueDate + ")"
+	case key == model.FutureKey:
+		return "Future"
+	case key == today.Format("2006-01-02"):
+		return "Today"
+	default:
+		return key
+	}
+}
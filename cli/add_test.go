@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+// withTempHome points HOME at a temp dir so commands read an isolated config,
+// and returns a data file path inside it.
+func withTempHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	return filepath.Join(home, "tasks.json")
+}
+
+// run executes a headless command against path and returns its exit code
+// and combined output.
+func run(t *testing.T, path string, args ...string) (int, string) {
+	t.Helper()
+	var out bytes.Buffer
+	code := Run(args, path, nil, &out, &out)
+	return code, out.String()
+}
+
+func dayKey(offset int) string {
+	return time.Now().AddDate(0, 0, offset).Format("2006-01-02")
+}
+
+func TestAddDefaultsToTodayAboveCompleted(t *testing.T) {
+	path := withTempHome(t)
+	today := dayKey(0)
+	existing := model.TodoData{today: {
+		{ID: "open", Title: "Open"},
+		{ID: "done", Title: "Done", Completed: true},
+	}}
+	if err := existing.Save(path, model.SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out := run(t, path, "add", "buy", "milk")
+	if code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	if !strings.Contains(out, `"buy milk"`) || !strings.Contains(out, "Today") {
+		t.Errorf("expected confirmation naming the task and Today, got %q", out)
+	}
+
+	data, err := model.Load(path, 0, model.Calendar{}.Today(), model.SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := data[today]
+	if len(got) != 3 || got[1].Title != "buy milk" || got[2].ID != "done" {
+		t.Fatalf("expected new task above the completed one, got %v", got)
+	}
+	if got[1].ID == "" || got[1].CreatedAt.IsZero() {
+		t.Errorf("expected a fresh ID and creation time, got %+v", got[1])
+	}
+}
+
+func TestAddSchedulesDatedTasksInFuture(t *testing.T) {
+	path := withTempHome(t)
+
+	for _, args := range [][]string{
+		{"add", "--in", "3", "in three"},
+		{"add", "by date", "--date", dayKey(3)},
+	} {
+		if code, out := run(t, path, args...); code != 0 {
+			t.Fatalf("args %v: code = %d, output %q", args, code, out)
+		}
+	}
+	if code, out := run(t, path, "add", "--future", "someday", "--notes", "Read the book first\n"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+
+	data, err := model.Load(path, 0, model.Calendar{}.Today(), model.SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	future := data[model.FutureKey]
+	if len(future) != 3 {
+		t.Fatalf("expected three Future tasks, got %v", future)
+	}
+	for _, task := range future[:2] {
+		if task.DueDate != dayKey(3) {
+			t.Errorf("task %q DueDate = %q, want %q", task.Title, task.DueDate, dayKey(3))
+		}
+	}
+	if future[2].DueDate != "" {
+		t.Errorf("--future should leave the task undated, got %q", future[2].DueDate)
+	}
+	if future[2].Notes != "Read the book first" {
+		t.Errorf("--notes should be stored trimmed, got %q", future[2].Notes)
+	}
+}
+
+func TestAddClampsPastDatesToToday(t *testing.T) {
+	path := withTempHome(t)
+	if code, out := run(t, path, "add", "--date", dayKey(-2), "late"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	data, err := model.Load(path, 0, model.Calendar{}.Today(), model.SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := data[dayKey(0)]; len(got) != 1 || got[0].DueDate != dayKey(0) {
+		t.Fatalf("expected past date to land on today, got %v", data)
+	}
+}
+
+func TestAddFollowsTheClock(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{"2026-03-06": {{ID: "1", Title: "Left on Friday"}}})
+	clock := model.FixedClock(time.Date(2026, 3, 7, 23, 59, 0, 0, time.Local))
+
+	var out bytes.Buffer
+	if code := Run([]string{"add", "--in", "1", "Sunday task"}, path, clock, &out, &out); code != 0 || !strings.Contains(out.String(), "Future (due 2026-03-08)") {
+		t.Fatalf("code = %d, output %q", code, out.String())
+	}
+	data, err := model.Load(path, 0, model.Calendar{Clock: clock}.Today(), model.SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := data["2026-03-07"]; len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("expected Friday's task rolled over to the clock's Saturday, got %v", data)
+	}
+}
+
+func TestAddSetsDeadlineWithoutScheduling(t *testing.T) {
+	path := withTempHome(t)
+	if code, out := run(t, path, "add", "Renew passport", "--deadline", "+10"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	tasks := loadData(t, path)[dayKey(0)]
+	if len(tasks) != 1 || tasks[0].Deadline != dayKey(10) || tasks[0].DueDate != "" {
+		t.Fatalf("expected today's task with a deadline, got %+v", tasks)
+	}
+}
+
+func TestAddRejectsBadInput(t *testing.T) {
+	path := withTempHome(t)
+	for _, args := range [][]string{
+		{"add"},
+		{"add", "--future", "--in", "2", "both"},
+		{"add", "--date", "someday", "bad date"},
+		{"add", "--in", "-1", "negative"},
+		{"add", "--deadline", "future", "no date"},
+	} {
+		if code, _ := run(t, path, args...); code != 1 {
+			t.Errorf("args %v: code = %d, want 1", args, code)
+		}
+	}
+}
+
+func TestRunRequiresStoragePath(t *testing.T) {
+	withTempHome(t)
+	code, out := run(t, "", "add", "task")
+	if code != 1 || !strings.Contains(out, "no storage path configured") {
+		t.Fatalf("expected setup guidance, got code %d output %q", code, out)
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+const backupUsage = "Usage: doitdoit backup list|restore <id>|diff <id>"
+
+func runBackup(args []string, e env, out, errOut io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(errOut, backupUsage)
+		return 1
+	}
+	fs := newFlagSet("backup "+args[0], backupUsage, errOut)
+	positional, err := parseArgs(fs, args[1:])
+	if err != nil {
+		return flagExitCode(err)
+	}
+	switch {
+	case args[0] == "list" && len(positional) == 0:
+		return runBackupList(e, out, errOut)
+	case args[0] == "restore" && len(positional) == 1:
+		return runBackupRestore(positional[0], e, out, errOut)
+	case args[0] == "diff" && len(positional) == 1:
+		return runBackupDiff(positional[0], e, out, errOut)
+	default:
+		fmt.Fprintln(errOut, backupUsage)
+		return 1
+	}
+}
+
+// runBackupList prints each backup of the task file, newest first, with the
+// number of tasks it holds.
+func runBackupList(e env, out, errOut io.Writer) int {
+	backups, err := model.Backups(e.path)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	if len(backups) == 0 {
+		fmt.Fprintln(out, "No backups yet; one is taken each time the task file is saved")
+		return 0
+	}
+	for _, backup := range backups {
+		tasks := "unreadable"
+		if data, err := model.LoadBackup(backup); err == nil {
+			count := 0
+			for _, list := range data {
+				count += len(list)
+			}
+			tasks = fmt.Sprintf("%d %s", count, plural(count, "task", "tasks"))
+		}
+		fmt.Fprintf(out, "%s  %s  %s\n", backup.ID, backup.Time.Format("Mon Jan 2 15:04:05"), tasks)
+	}
+	return 0
+}
+
+func runBackupRestore(id string, e env, out, errOut io.Writer) int {
+	replaced, err := model.RestoreBackup(e.path, id, e.save)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "Restored backup %s to %s\n", id, e.path)
+	if replaced != "" {
+		fmt.Fprintf(out, "The replaced version was backed up as %s; restore it to undo\n", replaced)
+	}
+	return 0
+}
+
+// runBackupDiff prints what has changed in the task file since a backup:
+// + for tasks added since, - for tasks removed, and ~ for tasks changed.
+func runBackupDiff(id string, e env, out, errOut io.Writer) int {
+	changes, err := model.DiffBackup(e.path, id)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "No changes since backup %s\n", id)
+		return 0
+	}
+	marks := map[string]string{"added": "+", "removed": "-", "changed": "~"}
+	for _, change := range changes {
+		fmt.Fprintf(out, "%s %-10s  %s\n", marks[change.Kind], change.Key, change.Title)
+	}
+	return 0
+}
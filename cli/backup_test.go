@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+func TestBackupListDiffAndRestore(t *testing.T) {
+	path := withTempHome(t)
+	if code, out := run(t, path, "backup", "list"); code != 0 || !strings.Contains(out, "No backups yet") {
+		t.Fatalf("expected no backups, got code %d output %q", code, out)
+	}
+
+	saveData(t, path, model.TodoData{dayKey(0): {{ID: "1", Title: "Original"}}})
+	if code, out := run(t, path, "add", "Second thought"); code != 0 {
+		t.Fatalf("add failed: %q", out)
+	}
+
+	code, out := run(t, path, "backup", "list")
+	if code != 0 || !strings.Contains(out, "1 task") {
+		t.Fatalf("expected one backup of one task, got code %d output %q", code, out)
+	}
+	id := strings.Fields(out)[0]
+
+	if code, out := run(t, path, "backup", "diff", id); code != 0 || !strings.Contains(out, "+ "+dayKey(0)+"  Second thought") {
+		t.Fatalf("expected the added task in the diff, got code %d output %q", code, out)
+	}
+	if code, out := run(t, path, "backup", "restore", id); code != 0 || !strings.Contains(out, "Restored backup "+id) || !strings.Contains(out, "backed up as") {
+		t.Fatalf("restore failed: code %d output %q", code, out)
+	}
+	if data := loadData(t, path); len(data[dayKey(0)]) != 1 {
+		t.Errorf("expected the backup restored, got %v", data)
+	}
+
+	if code, out := run(t, path, "backup", "restore", "nope"); code != 1 || !strings.Contains(out, "no backup") {
+		t.Errorf("expected an unknown ID to fail, got code %d output %q", code, out)
+	}
+	if code, out := run(t, path, "backup"); code != 1 || !strings.Contains(out, backupUsage) {
+		t.Errorf("expected usage, got code %d output %q", code, out)
+	}
+}
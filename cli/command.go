@@ -0,0 +1,211 @@
+// Package cli implements the headless subcommands that read and write the
+// task file without starting the TUI, so scripts, hooks, and launchers can
+// share the same data safely.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dtt101/doitdoit/config"
+	"github.com/dtt101/doitdoit/interchange"
+	"github.com/dtt101/doitdoit/model"
+)
+
+const usage = `Usage:
+  doitdoit add <title> [--date YYYY-MM-DD | --in N | --future] [--notes TEXT] [--repeat RULE]
+               [--deadline DATE]
+  doitdoit list|agenda [--days N] [--include-completed] [--tag TAG] [--project PROJECT]
+                      [--sort position|deadline] [--format text|plain|json | --json]
+  doitdoit done|undone|rm <task>
+  doitdoit move <task> <today|tomorrow|future|+N|YYYY-MM-DD|MM-DD>
+  doitdoit deadline <task> <today|tomorrow|+N|YYYY-MM-DD|MM-DD|none>
+  doitdoit stale [--after N] [--json]
+  doitdoit doctor [--fix] | doctor conflicts
+  doitdoit backup list|restore <id>|diff <id>
+  doitdoit import todotxt|markdown|csv|ics <file> [--dry-run] [--columns FIELD=HEADER,...]
+  doitdoit export markdown|todotxt|csv|html|ics [--events] [--from DATE] [--to DATE]
+                  [--tag TAG] [--project PROJECT] [--state open|completed] [--output FILE]
+
+A <task> is an ID, a unique title prefix, or date:index as numbered by list.`
+
+// commands maps each headless subcommand to its handler.
+var commands = map[string]func(args []string, env env, out, errOut io.Writer) int{
+	"add":      runAdd,
+	"list":     runList,
+	"agenda":   runList,
+	"done":     runDone,
+	"undone":   runUndone,
+	"rm":       runRm,
+	"move":     runMove,
+	"deadline": runDeadline,
+	"stale":    runStale,
+	"doctor":   runDoctor,
+	"backup":   runBackup,
+	"import":   runImport,
+	"export":   runExport,
+}
+
+// IsCommand reports whether name is a headless subcommand handled by Run.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// env is what every headless command needs to reach the task file.
+type env struct {
+	path          string
+	retentionDays int
+	save          model.SaveOptions
+	// theme is the configured theme name, for output styled like the TUI.
+	theme string
+	// cal decides what day it is, from the configured time zone and day
+	// start.
+	cal model.Calendar
+	// staleAfter is the configured staleness threshold; zero means the TUI
+	// marker is off.
+	staleAfter int
+}
+
+// Run executes a headless subcommand (args[0] is the command name) and
+// returns the process exit code. filePath is the -file override and may be
+// empty, and clock is nil for the system clock. Results are written to out
+// and problems to errOut.
+func Run(args []string, filePath string, clock model.Clock, out, errOut io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(errOut, usage)
+		return 1
+	}
+	run, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintln(errOut, usage)
+		return 1
+	}
+	// doctor checks the config, so it reports bad settings instead of
+	// stopping at them.
+	e, err := resolveEnv(filePath, args[0] == "doctor", clock, errOut)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	return run(args[1:], e, out, errOut)
+}
+
+// resolveEnv finds the task file without prompting: headless commands run
+// from scripts, so a missing setup is an error rather than a question.
+// Retention that has not been chosen yet means no pruning, matching the TUI's
+// guarantee that history is never pruned before an explicit choice. A
+// lenient caller gets an env even when settings are out of range, or when
+// the config cannot be read at all but -file names the task file. A
+// configured calendar feed is rewritten after every save; failing to write
+// it is reported to errOut without failing the command, whose change is
+// already saved.
+func resolveEnv(filePath string, lenient bool, clock model.Clock, errOut io.Writer) (env, error) {
+	cfg, err := config.ReadConfig()
+	if err != nil && lenient && filePath != "" {
+		cfg, err = &config.Config{}, nil
+	}
+	if err == nil && !lenient {
+		if problems := cfg.Problems(); len(problems) > 0 {
+			err = problems[0]
+		}
+	}
+	if err != nil {
+		return env{}, fmt.Errorf("loading config: %w", err)
+	}
+	path := cfg.StoragePath
+	if filePath != "" {
+		path = filePath
+	}
+	if path == "" {
+		return env{}, errors.New("no storage path configured; run doitdoit once to set one up or pass -file")
+	}
+	path, err = config.ExpandPath(path)
+	if err != nil {
+		return env{}, err
+	}
+	days, _ := cfg.Retention()
+	versions, backupDays := cfg.Backups()
+	save := model.SaveOptions{Backups: model.BackupPolicy{Versions: versions, Days: backupDays}}
+	if cfg.ICSFeed != "" {
+		feedPath, err := config.ExpandPath(cfg.ICSFeed)
+		if err != nil {
+			return env{}, err
+		}
+		feed := interchange.ICSFeed(feedPath, interchange.ICSOptions{Events: cfg.ICSFeedEvents})
+		save.AfterSave = func(data model.TodoData) error {
+			if err := feed(data); err != nil {
+				fmt.Fprintf(errOut, "Warning: %v\n", err)
+			}
+			return nil
+		}
+	}
+	// Only a lenient caller gets here with a bad zone, which falls back to
+	// the system's.
+	zone, _ := cfg.Location()
+	return env{
+		path:          path,
+		retentionDays: days,
+		save:          save,
+		theme:         cfg.Theme,
+		cal:           model.Calendar{Clock: clock, Zone: zone, DayStart: cfg.DayStart()},
+		staleAfter:    cfg.Staleness(),
+	}, nil
+}
+
+// parseArgs parses flags that may appear before, between, or after positional
+// arguments, which the standard flag package stops at. Everything after a
+// bare "--" is positional.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for i, arg := range args {
+		if arg == "--" {
+			rest = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet returns a flag set that reports errors through errOut with the
+// command's usage line rather than exiting the process.
+func newFlagSet(name, usageLine string, errOut io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	fs.Usage = func() {
+		fmt.Fprintln(errOut, usageLine)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// flagExitCode maps a flag parsing error to an exit code: asking for help
+// succeeds, anything else is a usage error.
+func flagExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	return 1
+}
+
+// joinTitle turns the positional words of a command into a task title, so
+// `doitdoit add buy milk` works without quoting.
+func joinTitle(words []string) string {
+	return strings.TrimSpace(strings.Join(words, " "))
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/dtt101/doitdoit/config"
+	"github.com/dtt101/doitdoit/model"
+	"github.com/dtt101/doitdoit/styles"
+)
+
+const doctorUsage = "Usage: doitdoit doctor [--fix] | doctor conflicts"
+
+func runDoctor(args []string, e env, out, errOut io.Writer) int {
+	if len(args) > 0 && args[0] == "conflicts" {
+		return runDoctorConflicts(args[1:], e, out, errOut)
+	}
+	return runDoctorCheck(args, e, out, errOut)
+}
+
+// runDoctorCheck validates the task file and config, and with --fix repairs
+// what it safely can. It exits non-zero while any problem remains.
+func runDoctorCheck(args []string, e env, out, errOut io.Writer) int {
+	fs := newFlagSet("doctor", doctorUsage, errOut)
+	fix := fs.Bool("fix", false, "repair what can be repaired safely, after backing up the task file")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return flagExitCode(err)
+	}
+	if len(positional) > 0 {
+		fmt.Fprintln(errOut, doctorUsage)
+		return 1
+	}
+
+	remaining := 0
+	fmt.Fprintf(out, "Task file %s\n", e.path)
+	remaining += checkPrivate(e.path, *fix, out)
+	if *fix {
+		report, err := model.RepairFile(e.path, e.cal.Today(), e.save)
+		if err != nil {
+			fmt.Fprintf(out, "  cannot be repaired: %v\n", err)
+			fmt.Fprintln(out, "  run doitdoit backup list to find a version to restore")
+			return 1
+		}
+		if report.Backup != "" {
+			fmt.Fprintf(out, "  backed up as %s before repairing\n", report.Backup)
+		}
+		for _, problem := range report.Fixed {
+			fmt.Fprintf(out, "  fixed %s: %s (%s)\n", problemPlace(problem), problem.Issue, problem.Fix)
+		}
+		remaining += printProblems(report.Remaining, out)
+	} else {
+		problems, err := model.Inspect(e.path, e.cal.Today())
+		if err != nil {
+			fmt.Fprintf(out, "  cannot be read: %v\n", err)
+			fmt.Fprintln(out, "  run doitdoit backup list to find a version to restore")
+			return 1
+		}
+		remaining += printProblems(problems, out)
+	}
+	if copies, err := model.ConflictedCopies(e.path); err == nil && len(copies) > 0 {
+		fmt.Fprintf(out, "  %d conflicted %s beside it; run doitdoit doctor conflicts\n", len(copies), plural(len(copies), "copy", "copies"))
+		remaining += len(copies)
+	}
+
+	if configPath, err := config.GetConfigPath(); err == nil {
+		fmt.Fprintf(out, "Config %s\n", configPath)
+		remaining += checkPrivate(configPath, *fix, out)
+		cfg, err := config.ReadConfig()
+		if err != nil {
+			fmt.Fprintf(out, "  cannot be read: %v\n", err)
+			remaining++
+		} else {
+			for _, problem := range cfg.Problems() {
+				fmt.Fprintf(out, "  %v (needs a manual edit)\n", problem)
+				remaining++
+			}
+			if cfg.Theme != "" && !styles.ValidThemeName(cfg.Theme) {
+				fmt.Fprintf(out, "  theme %q does not exist; choose one with doitdoit config theme\n", cfg.Theme)
+				remaining++
+			}
+		}
+	}
+
+	if remaining == 0 {
+		fmt.Fprintln(out, "No problems found")
+		return 0
+	}
+	fmt.Fprintf(out, "%d %s attention", remaining, plural(remaining, "problem needs", "problems need"))
+	if !*fix {
+		fmt.Fprint(out, "; doitdoit doctor --fix repairs those with a fix")
+	}
+	fmt.Fprintln(out)
+	return 1
+}
+
+// printProblems lists problems with the fix --fix would apply, returning how
+// many there were.
+func printProblems(problems []model.Problem, out io.Writer) int {
+	for _, problem := range problems {
+		fix := "needs a manual edit"
+		if problem.Fix != "" {
+			fix = "fix: " + problem.Fix
+		}
+		fmt.Fprintf(out, "  %s: %s (%s)\n", problemPlace(problem), problem.Issue, fix)
+	}
+	return len(problems)
+}
+
+func problemPlace(problem model.Problem) string {
+	if problem.Title == "" {
+		return problem.Key
+	}
+	return fmt.Sprintf("%s %q", problem.Key, problem.Title)
+}
+
+// checkPrivate reports a file that others can read, making it owner-only
+// when fix is set. It returns the number of problems left.
+func checkPrivate(path string, fix bool, out io.Writer) int {
+	if runtime.GOOS == "windows" {
+		return 0
+	}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(out, "  cannot be checked: %v\n", err)
+		return 1
+	}
+	mode := info.Mode().Perm()
+	if mode&0077 == 0 {
+		return 0
+	}
+	if fix {
+		if err := os.Chmod(path, 0600); err == nil {
+			fmt.Fprintf(out, "  fixed permissions: %#o is now owner-only\n", mode)
+			return 0
+		}
+	}
+	fmt.Fprintf(out, "  permissions %#o let other users read it (fix: make it owner-only)\n", mode)
+	return 1
+}
+
+// runDoctorConflicts folds sync providers' conflicted copies of the task file
+// back into it and archives them.
+func runDoctorConflicts(args []string, e env, out, errOut io.Writer) int {
+	fs := newFlagSet("doctor conflicts", doctorUsage, errOut)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return flagExitCode(err)
+	}
+	if len(positional) > 0 {
+		fmt.Fprintln(errOut, doctorUsage)
+		return 1
+	}
+
+	result, err := model.ReconcileConflictedCopies(e.path, e.retentionDays, e.cal.Today(), e.save)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	if len(result.Copies) == 0 {
+		fmt.Fprintln(out, "No conflicted copies found")
+		return 0
+	}
+
+	fmt.Fprintf(out, "Merged %d conflicted %s into %s: %d %s added\n",
+		len(result.Copies), plural(len(result.Copies), "copy", "copies"), e.path,
+		result.Added, plural(result.Added, "task", "tasks"))
+	for _, title := range result.Differed {
+		fmt.Fprintf(out, "  %q differed; kept this file's version\n", title)
+	}
+	for _, copyPath := range result.Copies {
+		fmt.Fprintf(out, "Archived %s\n", filepath.Base(copyPath))
+	}
+	fmt.Fprintf(out, "Archived copies are in %s\n", result.ArchiveDir)
+	return 0
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
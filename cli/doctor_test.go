@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dtt101/doitdoit/config"
+	"github.com/dtt101/doitdoit/model"
+)
+
+func TestDoctorConflictsMergesAndArchivesCopies(t *testing.T) {
+	path := withTempHome(t)
+	if code, out := run(t, path, "doctor", "conflicts"); code != 0 || !strings.Contains(out, "No conflicted copies") {
+		t.Fatalf("expected nothing to do, got code %d output %q", code, out)
+	}
+
+	saveData(t, path, model.TodoData{dayKey(0): {{ID: "1", Title: "Laptop task"}}})
+	copyPath := filepath.Join(filepath.Dir(path), "tasks (conflicted copy 2026-10-01).json")
+	saveData(t, copyPath, model.TodoData{dayKey(1): {{ID: "2", Title: "Phone task"}}})
+
+	code, out := run(t, path, "doctor", "conflicts")
+	if code != 0 || !strings.Contains(out, "1 task added") || !strings.Contains(out, "Archived tasks (conflicted copy 2026-10-01).json") {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	data := loadData(t, path)
+	if len(data[dayKey(0)]) != 1 || len(data[dayKey(1)]) != 1 {
+		t.Errorf("expected both tasks kept, got %v", data)
+	}
+	if _, err := os.Stat(copyPath); !os.IsNotExist(err) {
+		t.Errorf("expected the copy archived, got %v", err)
+	}
+
+	if code, out := run(t, path, "doctor", "conflicts", "extra"); code != 1 || !strings.Contains(out, doctorUsage) {
+		t.Errorf("expected usage for extra arguments, got code %d output %q", code, out)
+	}
+}
+
+func TestDoctorReportsAndRepairsProblems(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{dayKey(0): {{ID: "1", Title: "Fine"}}})
+	if code, out := run(t, path, "doctor"); code != 0 || !strings.Contains(out, "No problems found") {
+		t.Fatalf("expected a clean bill of health, got code %d output %q", code, out)
+	}
+
+	saveData(t, path, model.TodoData{
+		dayKey(-2): {{ID: "1", Title: "Stranded"}},
+		dayKey(0): {
+			{ID: "2", Title: "Done", Completed: true},
+			{ID: "1", Title: "Duplicate ID"},
+			{ID: "3", Title: "  ", Notes: "Keep me"},
+		},
+		"someday": {{ID: "4", Title: "Misfiled"}},
+	})
+	os.Chmod(path, 0644)
+
+	code, out := run(t, path, "doctor")
+	for _, want := range []string{"someday", "used more than once", "left on a past day", "below a completed one", "no title (needs a manual edit)", "permissions 0644", "doctor --fix"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the report, got %q", want, out)
+		}
+	}
+	if code != 1 {
+		t.Errorf("expected problems to fail the check, got code %d", code)
+	}
+
+	// Even with backups off, the repair keeps a copy and prunes none.
+	cfg := &config.Config{StoragePath: path}
+	cfg.SetBackups(0, 0)
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	older := filepath.Join(filepath.Dir(path), model.BackupsDir, "tasks-20200101-000000.json")
+	if err := os.MkdirAll(filepath.Dir(older), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(older, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out = run(t, path, "doctor", "--fix")
+	if code != 1 || !strings.Contains(out, "backed up as") || !strings.Contains(out, "1 problem needs attention") {
+		t.Fatalf("expected everything but the untitled task with notes fixed, got code %d output %q", code, out)
+	}
+	data := loadData(t, path)
+	if len(data[dayKey(-2)]) != 0 || len(data["someday"]) != 0 || len(data[model.FutureKey]) != 1 {
+		t.Errorf("expected the stranded and misfiled tasks moved, got %v", data)
+	}
+	if today := data[dayKey(0)]; len(today) != 4 || !today[3].Completed || today[0].ID == today[2].ID {
+		t.Errorf("expected completed tasks sunk and IDs made unique, got %v", today)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("expected owner-only permissions, got %v", info.Mode().Perm())
+	}
+	backups, _ := model.Backups(path)
+	if len(backups) != 2 || !strings.Contains(out, "backed up as "+backups[0].ID) {
+		t.Fatalf("expected the reported backup beside the older one, got %v and %q", backups, out)
+	}
+	if before, err := model.LoadBackup(backups[0]); err != nil || len(before[dayKey(-2)]) != 1 {
+		t.Errorf("expected the reported backup to hold the file before the repair, got %v, %v", before, err)
+	}
+}
+
+func TestDoctorReportsBadConfig(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{dayKey(0): {{ID: "1", Title: "Fine"}}})
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(`{"retention_days": -1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if code, out := run(t, path, "list"); code != 1 || !strings.Contains(out, "loading config") {
+		t.Fatalf("expected other commands to refuse the config, got code %d output %q", code, out)
+	}
+	code, out := run(t, path, "doctor")
+	if code != 1 || !strings.Contains(out, "retention_days must be zero") || !strings.Contains(out, "1 problem needs attention") {
+		t.Fatalf("expected doctor to report the bad setting, got code %d output %q", code, out)
+	}
+
+	if err := os.WriteFile(configPath, []byte(`{not json`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if code, out := run(t, path, "doctor"); code != 1 || !strings.Contains(out, "Config "+configPath+"\n  cannot be read") {
+		t.Fatalf("expected doctor to report the unreadable config, got code %d output %q", code, out)
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dtt101/doitdoit/interchange"
+	"github.com/dtt101/doitdoit/model"
+	"github.com/dtt101/doitdoit/styles"
+)
+
+const exportUsage = "Usage: doitdoit export markdown|todotxt|csv|html|ics [--events] [--from DATE] [--to DATE] [--tag TAG] [--project PROJECT] [--state open|completed] [--output FILE]"
+
+func runExport(args []string, e env, out, errOut io.Writer) int {
+	fs := newFlagSet("export", exportUsage, errOut)
+	from := fs.String("from", "", "only tasks on or after this day (YYYY-MM-DD or MM-DD)")
+	to := fs.String("to", "", "only tasks on or before this day (YYYY-MM-DD or MM-DD)")
+	tag := fs.String("tag", "", "only tasks with this #tag")
+	project := fs.String("project", "", "only tasks in this +project")
+	state := fs.String("state", "", "only open or completed tasks")
+	output := fs.String("output", "", "write to this file instead of standard output")
+	events := fs.Bool("events", false, "ics only: write all-day events instead of to-dos")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return flagExitCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(errOut, exportUsage)
+		return 1
+	}
+	if *state != "" && *state != "open" && *state != "completed" {
+		fmt.Fprintln(errOut, "--state must be open or completed.")
+		return 1
+	}
+
+	filter := interchange.Filter{
+		Tag:     *tag,
+		Project: *project,
+		State:   *state,
+	}
+	today := e.cal.Today()
+	for _, bound := range []struct {
+		value string
+		into  *string
+	}{{*from, &filter.From}, {*to, &filter.To}} {
+		if bound.value == "" {
+			continue
+		}
+		if *bound.into, err = model.NormalizeDueDate(bound.value, today); err != nil {
+			fmt.Fprintf(errOut, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	// Export what the TUI would show, without writing the file.
+	data, err := model.Snapshot(e.path, 1, today)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	theme, err := styles.ResolveTheme(e.theme)
+	if err != nil {
+		theme = styles.DefaultTheme()
+	}
+
+	var rendered bytes.Buffer
+	groups := interchange.Select(data, filter)
+	if err := interchange.Export(&rendered, positional[0], groups, interchange.ExportOptions{Theme: theme, Exported: e.cal.Now(), Location: e.cal.Location(), Events: *events}); err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	if *output == "" {
+		out.Write(rendered.Bytes())
+		return 0
+	}
+	if err := os.WriteFile(*output, rendered.Bytes(), 0600); err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
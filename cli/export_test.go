@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dtt101/doitdoit/config"
+	"github.com/dtt101/doitdoit/model"
+)
+
+func TestExportFormatsAndFilters(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{
+		dayKey(-1):      {{ID: "1", Title: "Yesterday's win #retro", Completed: true}},
+		dayKey(0):       {{ID: "2", Title: "Today's task"}},
+		model.FutureKey: {{ID: "3", Title: "Someday"}},
+	})
+
+	code, out := run(t, path, "export", "markdown")
+	if code != 0 || !strings.Contains(out, "- [x] Yesterday's win #retro") || !strings.Contains(out, "## Future\n\n- [ ] Someday") {
+		t.Fatalf("markdown: code %d output %q", code, out)
+	}
+	code, out = run(t, path, "export", "todotxt", "--from", dayKey(-7), "--to", dayKey(-1), "--state", "completed")
+	if code != 0 || out != "x "+dayKey(-1)+" Yesterday's win @retro\n" {
+		t.Fatalf("filtered todotxt: code %d output %q", code, out)
+	}
+	if code, out = run(t, path, "export", "csv", "--tag", "retro"); code != 0 || strings.Count(out, "\n") != 2 {
+		t.Fatalf("tagged csv: code %d output %q", code, out)
+	}
+
+	report := filepath.Join(t.TempDir(), "week.html")
+	if code, out = run(t, path, "export", "html", "--output", report); code != 0 || out != "" {
+		t.Fatalf("html: code %d output %q", code, out)
+	}
+	if page, err := os.ReadFile(report); err != nil || !strings.Contains(string(page), "Today&#39;s task") {
+		t.Errorf("html report = %q, %v", page, err)
+	}
+
+	code, out = run(t, path, "export", "ics", "--events")
+	if code != 0 || !strings.Contains(out, "DTSTART;VALUE=DATE:"+strings.ReplaceAll(dayKey(0), "-", "")) || strings.Contains(out, "Someday") {
+		t.Fatalf("ics: code %d output %q", code, out)
+	}
+
+	if code, out := run(t, path, "export", "pdf"); code != 1 || !strings.Contains(out, "unknown format") {
+		t.Errorf("expected an unknown format error, got code %d output %q", code, out)
+	}
+	if code, out := run(t, path, "export", "csv", "--state", "maybe"); code != 1 || !strings.Contains(out, "--state") {
+		t.Errorf("expected a state error, got code %d output %q", code, out)
+	}
+}
+
+func TestHeadlessSavesRewriteTheCalendarFeed(t *testing.T) {
+	path := withTempHome(t)
+	feed := filepath.Join(t.TempDir(), "plan.ics")
+	if err := config.SaveConfig(&config.Config{StoragePath: path, ICSFeed: feed}); err != nil {
+		t.Fatal(err)
+	}
+
+	if code, out := run(t, path, "add", "Pick up parcel"); code != 0 {
+		t.Fatalf("add: code %d output %q", code, out)
+	}
+	raw, err := os.ReadFile(feed)
+	if err != nil || !strings.Contains(string(raw), "SUMMARY:Pick up parcel") {
+		t.Fatalf("expected the feed rewritten by add, got %q (%v)", raw, err)
+	}
+
+	// A feed that cannot be written warns, but the task is still saved.
+	if err := os.Remove(feed); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(feed, 0700); err != nil {
+		t.Fatal(err)
+	}
+	code, out := run(t, path, "add", "Water plants")
+	if code != 0 || !strings.Contains(out, "Warning: updating calendar feed") {
+		t.Fatalf("expected a warning, got code %d output %q", code, out)
+	}
+	if data := loadData(t, path); len(data[dayKey(0)]) != 2 {
+		t.Errorf("expected both tasks saved, got %v", data)
+	}
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/interchange"
+	"github.com/dtt101/doitdoit/model"
+)
+
+const importUsage = "Usage: doitdoit import todotxt|markdown|csv|ics <file> [--dry-run] [--columns FIELD=HEADER,...]"
+
+func runImport(args []string, e env, out, errOut io.Writer) int {
+	fs := newFlagSet("import", importUsage, errOut)
+	dryRun := fs.Bool("dry-run", false, "print what would be imported without changing the task file")
+	columns := fs.String("columns", "", "CSV only: map fields ("+strings.Join(interchange.CSVFields(), ", ")+") to headers, e.g. title=Task,date=Due")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return flagExitCode(err)
+	}
+	if len(positional) != 2 {
+		fmt.Fprintln(errOut, importUsage)
+		return 1
+	}
+	format, source := positional[0], positional[1]
+
+	opts := interchange.ParseOptions{Columns: make(map[string]string), Location: e.cal.Location()}
+	for _, pair := range strings.Split(*columns, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		field, header, ok := strings.Cut(pair, "=")
+		if !ok {
+			fmt.Fprintf(errOut, "Error: --columns takes FIELD=HEADER pairs, got %q\n", pair)
+			return 1
+		}
+		opts.Columns[strings.ToLower(strings.TrimSpace(field))] = strings.TrimSpace(header)
+	}
+
+	file, err := os.Open(source)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	defer file.Close()
+	entries, err := interchange.Parse(format, file, opts)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %s: %v\n", source, err)
+		return 1
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(out, "No tasks found in %s\n", source)
+		return 0
+	}
+
+	today := e.cal.Today()
+	var placed []interchange.Placed
+	importEntries := func(data model.TodoData) (bool, error) {
+		placed, err = interchange.Import(data, entries, e.cal)
+		return err == nil, err
+	}
+	if *dryRun {
+		err = model.Preview(e.path, e.retentionDays, today, importEntries)
+	} else {
+		err = model.Update(e.path, e.retentionDays, today, e.save, importEntries)
+	}
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	counts := make(map[string]int)
+	for _, p := range placed {
+		counts[p.Status]++
+	}
+	breakdown := ""
+	if counts[interchange.Added] != len(placed) {
+		breakdown = fmt.Sprintf(" (%d new, %d updated, %d unchanged)", counts[interchange.Added], counts[interchange.Updated], counts[interchange.Unchanged])
+	}
+	fmt.Fprintf(out, "%s %d %s from %s%s:\n", verb, len(placed), plural(len(placed), "task", "tasks"), source, breakdown)
+	for _, p := range placed {
+		mark := " "
+		if p.Task.Completed {
+			mark = "x"
+		}
+		note := ""
+		if p.Status != interchange.Added {
+			note = "  (" + p.Status + ")"
+		}
+		fmt.Fprintf(out, "  [%s] %s  %s%s\n", mark, p.Task.Title, describePlacement(p.Key, p.Task.DueDate, today), note)
+	}
+	warnOutsideRetention(placed, e.retentionDays, today, errOut)
+	return 0
+}
+
+// warnOutsideRetention points out imported tasks that the retention period
+// already covers, since the next load prunes them.
+func warnOutsideRetention(placed []interchange.Placed, retentionDays int, today time.Time, errOut io.Writer) {
+	expired := 0
+	for _, p := range placed {
+		if model.OutsideRetention(p.Key, p.Task, retentionDays, today) {
+			expired++
+		}
+	}
+	if expired > 0 {
+		fmt.Fprintf(errOut, "Warning: %d completed %s outside the %d-day retention period; the next load prunes %s\n",
+			expired, plural(expired, "task falls", "tasks fall"), retentionDays, plural(expired, "it", "them"))
+	}
+}
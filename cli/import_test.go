@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dtt101/doitdoit/config"
+	"github.com/dtt101/doitdoit/model"
+)
+
+func TestImportDryRunThenImport(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{dayKey(0): {{ID: "1", Title: "Existing"}}})
+	source := filepath.Join(t.TempDir(), "todo.txt")
+	todo := "(A) Urgent thing\nx " + dayKey(-1) + " Done yesterday\nLater due:" + dayKey(10) + "\n"
+	if err := os.WriteFile(source, []byte(todo), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out := run(t, path, "import", "todotxt", source, "--dry-run")
+	if code != 0 || !strings.Contains(out, "Would import 3 tasks") || !strings.Contains(out, "[x] Done yesterday  "+dayKey(-1)) {
+		t.Fatalf("dry run: code %d output %q", code, out)
+	}
+	if data := loadData(t, path); len(data) != 1 || len(data[dayKey(0)]) != 1 {
+		t.Fatalf("a dry run must not write, got %v", data)
+	}
+
+	code, out = run(t, path, "import", "todotxt", source)
+	if code != 0 || !strings.Contains(out, "Imported 3 tasks") || !strings.Contains(out, "Future (due "+dayKey(10)+")") {
+		t.Fatalf("import: code %d output %q", code, out)
+	}
+	data := loadData(t, path)
+	if len(data[dayKey(0)]) != 1 || len(data[dayKey(-1)]) != 1 || len(data[model.FutureKey]) != 2 {
+		t.Errorf("unexpected placement: %v", data)
+	}
+}
+
+func TestImportDryRunMatchesImportAndWarnsAboutRetention(t *testing.T) {
+	path := withTempHome(t)
+	cfg := &config.Config{StoragePath: path}
+	cfg.SetRetention(7)
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	saveData(t, path, model.TodoData{dayKey(0): {{ID: "1", Title: "Existing"}}})
+	source := filepath.Join(t.TempDir(), "todo.txt")
+	todo := "x " + dayKey(-30) + " Filed taxes\nx " + dayKey(-1) + " Done yesterday\nNew thing\n"
+	if err := os.WriteFile(source, []byte(todo), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, preview := run(t, path, "import", "todotxt", source, "--dry-run")
+	if code != 0 || !strings.Contains(preview, "Warning: 1 completed task falls outside the 7-day retention period") {
+		t.Fatalf("dry run: code %d output %q", code, preview)
+	}
+	code, out := run(t, path, "import", "todotxt", source)
+	if code != 0 || out != strings.Replace(preview, "Would import", "Imported", 1) {
+		t.Fatalf("expected the import to match its dry run %q, got code %d output %q", preview, code, out)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	path := withTempHome(t)
+	source := filepath.Join(t.TempDir(), "tasks.csv")
+	os.WriteFile(source, []byte("Thing\nBuy milk\n"), 0600)
+
+	if code, out := run(t, path, "import", "csv", source); code != 1 || !strings.Contains(out, "no title column") {
+		t.Errorf("expected a missing column error, got code %d output %q", code, out)
+	}
+	if code, out := run(t, path, "import", "csv", source, "--columns", "title=Thing"); code != 0 || !strings.Contains(out, "Imported 1 task ") {
+		t.Errorf("expected the mapped import to succeed, got code %d output %q", code, out)
+	}
+	if code, out := run(t, path, "import", "rtf", source); code != 1 || !strings.Contains(out, "unknown format") {
+		t.Errorf("expected an unknown format error, got code %d output %q", code, out)
+	}
+	if code, out := run(t, path, "import", "csv"); code != 1 || !strings.Contains(out, importUsage) {
+		t.Errorf("expected usage, got code %d output %q", code, out)
+	}
+}
+
+func TestImportICSTwiceUpdatesInsteadOfCopying(t *testing.T) {
+	path := withTempHome(t)
+	source := filepath.Join(t.TempDir(), "tasks.ics")
+	calendar := func(summary string) string {
+		return "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:renew@example.com\r\nSUMMARY:" + summary +
+			"\r\nDUE;VALUE=DATE:" + strings.ReplaceAll(dayKey(2), "-", "") + "\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+	}
+	os.WriteFile(source, []byte(calendar("Renew passport")), 0600)
+
+	if code, out := run(t, path, "import", "ics", source); code != 0 || !strings.Contains(out, "Imported 1 task ") {
+		t.Fatalf("first import: code %d output %q", code, out)
+	}
+	if code, out := run(t, path, "import", "ics", source, "--dry-run"); code != 0 || !strings.Contains(out, "(0 new, 0 updated, 1 unchanged)") {
+		t.Fatalf("dry run of a re-import: code %d output %q", code, out)
+	}
+	os.WriteFile(source, []byte(calendar("Renew passport and visa")), 0600)
+	if code, out := run(t, path, "import", "ics", source); code != 0 || !strings.Contains(out, "Renew passport and visa  Future (due "+dayKey(2)+")  (updated)") {
+		t.Fatalf("re-import: code %d output %q", code, out)
+	}
+	data := loadData(t, path)
+	if future := data[model.FutureKey]; len(future) != 1 || future[0].Title != "Renew passport and visa" || future[0].UID != "renew@example.com" {
+		t.Errorf("expected one updated task, got %v", data)
+	}
+}
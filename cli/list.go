@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+// defaultDays matches the TUI's default viewport, so `list` and date:index
+// references see the same days the TUI opens with.
+const defaultDays = 3
+
+const listUsage = "Usage: doitdoit list|agenda [--days N] [--include-completed] [--tag TAG] [--project PROJECT] [--sort position|deadline] [--format text|plain|json | --json]"
+
+// agendaDay is one dated section of the agenda.
+type agendaDay struct {
+	Date  string       `json:"date"`
+	Label string       `json:"label"`
+	Tasks []model.Task `json:"tasks"`
+}
+
+// agenda is the JSON shape of `doitdoit list --json`: the visible days in
+// order followed by the Future list, grouped the way the TUI shows them.
+type agenda struct {
+	Today  string       `json:"today"`
+	Days   []agendaDay  `json:"days"`
+	Future []model.Task `json:"future"`
+}
+
+// taskFilter selects which tasks a listing shows. Tag and project filters
+// combine, so both must match when both are given.
+type taskFilter struct {
+	includeCompleted bool
+	tag              string
+	project          string
+}
+
+func (f taskFilter) keep(task model.Task) bool {
+	if task.Completed && !f.includeCompleted {
+		return false
+	}
+	if f.tag != "" && !task.HasTag(f.tag) {
+		return false
+	}
+	return f.project == "" || task.HasProject(f.project)
+}
+
+func runList(args []string, e env, out, errOut io.Writer) int {
+	fs := newFlagSet("list", listUsage, errOut)
+	days := fs.Int("days", defaultDays, "number of days to show, starting today")
+	includeCompleted := fs.Bool("include-completed", false, "also show completed tasks")
+	format := fs.String("format", "text", "output format: text, plain, or json")
+	asJSON := fs.Bool("json", false, "shorthand for --format json")
+	tag := fs.String("tag", "", "only show tasks with this #tag")
+	project := fs.String("project", "", "only show tasks in this +project")
+	sortBy := fs.String("sort", "position", "order within each day: position or deadline")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return flagExitCode(err)
+	}
+	if len(positional) > 0 {
+		fmt.Fprintln(errOut, listUsage)
+		return 1
+	}
+	if *days < 1 {
+		fmt.Fprintln(errOut, "--days must be at least 1.")
+		return 1
+	}
+	if *asJSON {
+		*format = "json"
+	}
+	if *format != "text" && *format != "plain" && *format != "json" {
+		fmt.Fprintf(errOut, "Unknown format %q; use text, plain, or json.\n", *format)
+		return 1
+	}
+	if *sortBy != "position" && *sortBy != "deadline" {
+		fmt.Fprintf(errOut, "Unknown sort %q; use position or deadline.\n", *sortBy)
+		return 1
+	}
+
+	today := e.cal.Today()
+	data, err := model.Snapshot(e.path, *days, today)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error loading tasks: %v\n", err)
+		return 1
+	}
+	filter := taskFilter{includeCompleted: *includeCompleted, tag: *tag, project: *project}
+	a := buildAgenda(data, *days, filter, today)
+	byDeadline := *sortBy == "deadline"
+	if byDeadline {
+		for i := range a.Days {
+			sortByDeadline(a.Days[i].Tasks)
+		}
+		sortByDeadline(a.Future)
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(a); err != nil {
+			fmt.Fprintf(errOut, "Error encoding agenda: %v\n", err)
+			return 1
+		}
+	case "plain":
+		writePlainAgenda(out, a)
+	default:
+		writeTextAgenda(out, data, a, filter, byDeadline, today)
+		warnOverdue(errOut, data, filter, today)
+	}
+	return 0
+}
+
+func buildAgenda(data model.TodoData, days int, filter taskFilter, today time.Time) agenda {
+	first := today
+	a := agenda{Today: first.Format("2006-01-02"), Future: visibleTasks(data[model.FutureKey], filter)}
+	for i := range days {
+		date := first.AddDate(0, 0, i)
+		key := date.Format("2006-01-02")
+		a.Days = append(a.Days, agendaDay{
+			Date:  key,
+			Label: dayLabel(date, i),
+			Tasks: visibleTasks(data[key], filter),
+		})
+	}
+	return a
+}
+
+// visibleTasks keeps the tasks the filter selects. It never returns nil, so
+// empty days encode as [] rather than null.
+func visibleTasks(tasks []model.Task, filter taskFilter) []model.Task {
+	visible := make([]model.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if filter.keep(task) {
+			visible = append(visible, task)
+		}
+	}
+	return visible
+}
+
+// dayLabel matches the TUI's column headers.
+func dayLabel(date time.Time, offset int) string {
+	if offset == 0 {
+		return "Today"
+	}
+	return date.Format("Mon, Jan 02")
+}
+
+// writeTextAgenda prints each section with the 1-based position of every
+// task in its list, which is what date:index arguments refer to.
+// With byDeadline, tasks are reordered but keep their numbers. Deadline
+// badges count from today.
+func writeTextAgenda(out io.Writer, data model.TodoData, a agenda, filter taskFilter, byDeadline bool, today time.Time) {
+	sections := make([]agendaDay, 0, len(a.Days)+1)
+	sections = append(sections, a.Days...)
+	sections = append(sections, agendaDay{Date: model.FutureKey, Label: "Future"})
+
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintln(out, section.Label)
+		var shown []numbered
+		for idx, task := range data[section.Date] {
+			if filter.keep(task) {
+				shown = append(shown, numbered{idx + 1, task})
+			}
+		}
+		if byDeadline {
+			slices.SortStableFunc(shown, func(a, b numbered) int { return compareDeadlines(a.task, b.task) })
+		}
+		for _, n := range shown {
+			fmt.Fprintf(out, "  %d. %s\n", n.position, taskLine(n.task, section.Date == model.FutureKey, today))
+		}
+		if len(shown) == 0 {
+			fmt.Fprintln(out, "  No tasks")
+		}
+	}
+}
+
+// numbered is a task with its 1-based position in its list.
+type numbered struct {
+	position int
+	task     model.Task
+}
+
+// compareDeadlines orders tasks by deadline, earliest first, with tasks that
+// have none after every task that has one.
+func compareDeadlines(a, b model.Task) int {
+	switch {
+	case a.Deadline == b.Deadline:
+		return 0
+	case a.Deadline == "":
+		return 1
+	case b.Deadline == "":
+		return -1
+	}
+	return strings.Compare(a.Deadline, b.Deadline)
+}
+
+// sortByDeadline orders tasks with compareDeadlines, keeping their list
+// order otherwise.
+func sortByDeadline(tasks []model.Task) {
+	slices.SortStableFunc(tasks, compareDeadlines)
+}
+
+// warnOverdue lists the open tasks the filter selects whose deadline has
+// passed, wherever they are filed, so they are noticed even when their day
+// is out of view.
+func warnOverdue(errOut io.Writer, data model.TodoData, filter taskFilter, today time.Time) {
+	var overdue []model.Task
+	for _, tasks := range data {
+		for _, task := range tasks {
+			if _, late := task.DeadlineBadge(today); late && filter.keep(task) {
+				overdue = append(overdue, task)
+			}
+		}
+	}
+	if len(overdue) == 0 {
+		return
+	}
+	slices.SortFunc(overdue, func(a, b model.Task) int {
+		if c := compareDeadlines(a, b); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Title, b.Title)
+	})
+	noun := "tasks"
+	if len(overdue) == 1 {
+		noun = "task"
+	}
+	fmt.Fprintf(errOut, "\nWarning: %d overdue %s:\n", len(overdue), noun)
+	for _, task := range overdue {
+		badge, _ := task.DeadlineBadge(today)
+		fmt.Fprintf(errOut, "  %s (deadline %s, %s)\n", task.Title, task.Deadline, badge)
+	}
+}
+
+// writePlainAgenda prints one undecorated task per line as
+// "<date-or-Future>\t<title>", for status bars and grep.
+func writePlainAgenda(out io.Writer, a agenda) {
+	for _, day := range a.Days {
+		for _, task := range day.Tasks {
+			fmt.Fprintf(out, "%s\t%s\n", day.Date, task.Title)
+		}
+	}
+	for _, task := range a.Future {
+		fmt.Fprintf(out, "%s\t%s\n", model.FutureKey, task.Title)
+	}
+}
+
+func taskLine(task model.Task, inFuture bool, today time.Time) string {
+	var line strings.Builder
+	if task.Completed {
+		line.WriteString("[x] ")
+	} else {
+		line.WriteString("[ ] ")
+	}
+	line.WriteString(task.Title)
+	if inFuture && task.DueDate != "" {
+		fmt.Fprintf(&line, " (%s)", task.DueDate)
+	}
+	if task.Repeat != "" {
+		fmt.Fprintf(&line, " [repeats %s]", task.Repeat)
+	}
+	if badge, _ := task.DeadlineBadge(today); badge != "" {
+		fmt.Fprintf(&line, " [%s]", badge)
+	}
+	return line.String()
+}
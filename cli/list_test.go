@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+func TestListGroupsLikeTheTUIWithoutWriting(t *testing.T) {
+	path := withTempHome(t)
+	data := model.TodoData{
+		dayKey(-1): {{ID: "late", Title: "Rolled over"}},
+		dayKey(0): {
+			{ID: "open", Title: "Open today"},
+			{ID: "done", Title: "Done today", Completed: true},
+		},
+		model.FutureKey: {
+			{ID: "soon", Title: "Due tomorrow", DueDate: dayKey(1)},
+			{ID: "later", Title: "Due later", DueDate: dayKey(30)},
+			{ID: "idea", Title: "Someday"},
+		},
+	}
+	if err := data.Save(path, model.SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, out := run(t, path, "list")
+	if code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	for _, want := range []string{"Today", "1. [ ] Open today", "3. [ ] Rolled over", "[ ] Due tomorrow", "Future", "[ ] Due later (" + dayKey(30) + ")", "[ ] Someday"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Done today") {
+		t.Errorf("completed tasks should be hidden by default:\n%s", out)
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Error("list must not rewrite the data file")
+	}
+}
+
+func TestListJSONIncludesCompletedOnRequest(t *testing.T) {
+	path := withTempHome(t)
+	data := model.TodoData{dayKey(0): {
+		{ID: "open", Title: "Open"},
+		{ID: "done", Title: "Done", Completed: true},
+	}}
+	if err := data.Save(path, model.SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out := run(t, path, "agenda", "--json", "--days", "2", "--include-completed")
+	if code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	var got agenda
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if got.Today != dayKey(0) || len(got.Days) != 2 || got.Days[1].Date != dayKey(1) {
+		t.Fatalf("unexpected days: %+v", got)
+	}
+	if len(got.Days[0].Tasks) != 2 || got.Days[0].Label != "Today" {
+		t.Errorf("expected both tasks under Today, got %+v", got.Days[0])
+	}
+	if got.Future == nil || got.Days[1].Tasks == nil {
+		t.Error("empty lists should encode as [] rather than null")
+	}
+}
+
+func TestListPlainFormat(t *testing.T) {
+	path := withTempHome(t)
+	data := model.TodoData{
+		dayKey(0):       {{ID: "a", Title: "Today task"}},
+		model.FutureKey: {{ID: "b", Title: "Idea"}},
+	}
+	if err := data.Save(path, model.SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out := run(t, path, "list", "--format", "plain")
+	if code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	want := dayKey(0) + "\tToday task\nFuture\tIdea\n"
+	if out != want {
+		t.Errorf("plain output = %q, want %q", out, want)
+	}
+}
+
+func TestListFiltersByTagAndProject(t *testing.T) {
+	path := withTempHome(t)
+	data := model.TodoData{
+		dayKey(0): {
+			model.NewTask("Write spec +launch #writing", time.Now()),
+			model.NewTask("Email landlord #home", time.Now()),
+			model.NewTask("Review copy +launch", time.Now()),
+		},
+		model.FutureKey: {model.NewTask("Blog post #writing", time.Now())},
+	}
+	if err := data.Save(path, model.SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out := run(t, path, "list", "--project", "+Launch", "--format", "plain")
+	if code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	want := dayKey(0) + "\tWrite spec +launch #writing\n" + dayKey(0) + "\tReview copy +launch\n"
+	if out != want {
+		t.Errorf("project filter output = %q, want %q", out, want)
+	}
+
+	code, out = run(t, path, "list", "--tag", "writing", "--project", "launch", "--json")
+	if code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	var a agenda
+	if err := json.Unmarshal([]byte(out), &a); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Days[0].Tasks) != 1 || len(a.Future) != 0 {
+		t.Fatalf("expected tag and project filters to combine, got %+v", a)
+	}
+	if task := a.Days[0].Tasks[0]; task.Tags[0] != "writing" || task.Projects[0] != "launch" {
+		t.Errorf("expected parsed labels in JSON, got %+v", task)
+	}
+}
+
+func TestListRejectsBadFlags(t *testing.T) {
+	path := withTempHome(t)
+	for _, args := range [][]string{
+		{"list", "--days", "0"},
+		{"list", "--format", "yaml"},
+		{"list", "extra"},
+	} {
+		if code, _ := run(t, path, args...); code != 1 {
+			t.Errorf("args %v: code = %d, want 1", args, code)
+		}
+	}
+}
+
+func TestListShowsSortsAndWarnsOnDeadlines(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{
+		dayKey(0): {
+			{ID: "1", Title: "Water plants"},
+			{ID: "2", Title: "Renew passport", Deadline: dayKey(5)},
+			{ID: "3", Title: "File taxes", Deadline: dayKey(-2)},
+			{ID: "4", Title: "Old invoice", Deadline: dayKey(-9), Completed: true},
+		},
+	})
+
+	var out, errOut bytes.Buffer
+	if code := Run([]string{"list", "--sort", "deadline"}, path, nil, &out, &errOut); code != 0 {
+		t.Fatalf("code = %d, stderr %q", code, errOut.String())
+	}
+	want := "Today\n" +
+		"  3. [ ] File taxes [overdue 2d]\n" +
+		"  2. [ ] Renew passport [due in 5d]\n" +
+		"  1. [ ] Water plants\n"
+	if !strings.HasPrefix(out.String(), want) {
+		t.Errorf("sorted output = %q, want prefix %q", out.String(), want)
+	}
+	if got := errOut.String(); !strings.Contains(got, "1 overdue task:") || !strings.Contains(got, "File taxes (deadline "+dayKey(-2)) || strings.Contains(got, "Old invoice") {
+		t.Errorf("expected a warning about the open overdue task only, got %q", got)
+	}
+
+	code, plain := run(t, path, "list", "--sort", "deadline", "--format", "plain")
+	if code != 0 || !strings.HasPrefix(plain, dayKey(0)+"\tFile taxes\n") || strings.Contains(plain, "Warning") {
+		t.Errorf("plain output should be sorted and carry no warning, got %q", plain)
+	}
+	if code, _ := run(t, path, "list", "--sort", "priority"); code != 1 {
+		t.Errorf("unknown sort: code = %d, want 1", code)
+	}
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+const (
+	doneUsage     = "Usage: doitdoit done <task>"
+	undoneUsage   = "Usage: doitdoit undone <task>"
+	rmUsage       = "Usage: doitdoit rm <task>"
+	moveUsage     = "Usage: doitdoit move <task> <today|tomorrow|future|+N|YYYY-MM-DD|MM-DD>"
+	deadlineUsage = "Usage: doitdoit deadline <task> <today|tomorrow|+N|YYYY-MM-DD|MM-DD|none>"
+)
+
+// changeFunc applies one command to the resolved task and returns the
+// message to print, and false when nothing changed.
+type changeFunc func(data model.TodoData, ref taskRef) (string, bool, error)
+
+func runDone(args []string, e env, out, errOut io.Writer) int {
+	ref, code, ok := parseTaskArgs("done", doneUsage, args, errOut)
+	if !ok {
+		return code
+	}
+	today := e.cal.Today()
+	incomplete := func(task model.Task) bool { return !task.Completed }
+	return changeTask(e, today, ref, incomplete, func(data model.TodoData, r taskRef) (string, bool, error) {
+		if r.task.Completed {
+			return fmt.Sprintf("%q is already completed", r.task.Title), false, nil
+		}
+		message := fmt.Sprintf("Completed %q (%s)", r.task.Title, describePlacement(r.key, r.task.DueDate, today))
+		if next, ok := r.task.NextOccurrence(r.key, today); ok {
+			message += "; next on " + next
+		}
+		data.Toggle(r.key, r.idx, e.cal)
+		return message, true, nil
+	}, out, errOut)
+}
+
+func runUndone(args []string, e env, out, errOut io.Writer) int {
+	ref, code, ok := parseTaskArgs("undone", undoneUsage, args, errOut)
+	if !ok {
+		return code
+	}
+	today := e.cal.Today()
+	completed := func(task model.Task) bool { return task.Completed }
+	return changeTask(e, today, ref, completed, func(data model.TodoData, r taskRef) (string, bool, error) {
+		if !r.task.Completed {
+			return fmt.Sprintf("%q is not completed", r.task.Title), false, nil
+		}
+		key, _ := data.Reopen(r.key, r.idx, e.cal)
+		return fmt.Sprintf("Reopened %q (%s)", r.task.Title, describePlacement(key, r.task.DueDate, today)), true, nil
+	}, out, errOut)
+}
+
+func runRm(args []string, e env, out, errOut io.Writer) int {
+	ref, code, ok := parseTaskArgs("rm", rmUsage, args, errOut)
+	if !ok {
+		return code
+	}
+	today := e.cal.Today()
+	return changeTask(e, today, ref, anyTask, func(data model.TodoData, r taskRef) (string, bool, error) {
+		data.Remove(r.key, r.idx)
+		return fmt.Sprintf("Deleted %q from %s", r.task.Title, describePlacement(r.key, r.task.DueDate, today)), true, nil
+	}, out, errOut)
+}
+
+func runMove(args []string, e env, out, errOut io.Writer) int {
+	fs := newFlagSet("move", moveUsage, errOut)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return flagExitCode(err)
+	}
+	if len(positional) < 2 {
+		fmt.Fprintln(errOut, moveUsage)
+		return 1
+	}
+	ref := joinTitle(positional[:len(positional)-1])
+	today := e.cal.Today()
+	date, err := resolveDestination(positional[len(positional)-1], today)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+
+	return changeTask(e, today, ref, anyTask, func(data model.TodoData, r taskRef) (string, bool, error) {
+		from := describePlacement(r.key, r.task.DueDate, today)
+		key, moved, err := data.Reschedule(r.key, r.idx, date, today, today)
+		if err != nil || !moved {
+			return fmt.Sprintf("%q is already in %s", r.task.Title, from), false, err
+		}
+		task := data[key][indexOf(data[key], r.task.ID)]
+		return fmt.Sprintf("Moved %q from %s to %s", r.task.Title, from, describePlacement(key, task.DueDate, today)), true, nil
+	}, out, errOut)
+}
+
+// runDeadline sets or clears a task's deadline. Unlike move, it never changes
+// the day the task is planned for.
+func runDeadline(args []string, e env, out, errOut io.Writer) int {
+	fs := newFlagSet("deadline", deadlineUsage, errOut)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return flagExitCode(err)
+	}
+	if len(positional) < 2 {
+		fmt.Fprintln(errOut, deadlineUsage)
+		return 1
+	}
+	ref := joinTitle(positional[:len(positional)-1])
+	today := e.cal.Today()
+	date, err := resolveDeadline(positional[len(positional)-1], today)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+
+	return changeTask(e, today, ref, anyTask, func(data model.TodoData, r taskRef) (string, bool, error) {
+		switch {
+		case r.task.Deadline == date && date == "":
+			return fmt.Sprintf("%q has no deadline", r.task.Title), false, nil
+		case r.task.Deadline == date:
+			return fmt.Sprintf("%q is already due by %s", r.task.Title, date), false, nil
+		}
+		data[r.key][r.idx].Deadline = date
+		if date == "" {
+			return fmt.Sprintf("Cleared the deadline for %q", r.task.Title), true, nil
+		}
+		return fmt.Sprintf("%q is now due by %s", r.task.Title, date), true, nil
+	}, out, errOut)
+}
+
+// parseTaskArgs parses a command whose positional words name a single task.
+// When ok is false the command should exit with code.
+func parseTaskArgs(name, usageLine string, args []string, errOut io.Writer) (ref string, code int, ok bool) {
+	fs := newFlagSet(name, usageLine, errOut)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return "", flagExitCode(err), false
+	}
+	ref = joinTitle(positional)
+	if ref == "" {
+		fmt.Fprintln(errOut, usageLine)
+		return "", 1, false
+	}
+	return ref, 0, true
+}
+
+func anyTask(model.Task) bool { return true }
+
+// changeTask runs one locked load-change-save cycle as of today. The data
+// goes through the same rollover and retention as the TUI's load, and dated
+// Future tasks are surfaced through the referenced day so positions match
+// `list`.
+func changeTask(e env, today time.Time, ref string, eligible func(model.Task) bool, change changeFunc, out, errOut io.Writer) int {
+	refKey := ""
+	if keyPart, _, ok := strings.Cut(ref, ":"); ok {
+		refKey, _ = resolveDayKey(keyPart, today)
+	}
+
+	var message string
+	err := model.Update(e.path, e.retentionDays, today, e.save, func(data model.TodoData) (bool, error) {
+		distributeThrough(data, refKey, today)
+		r, err := findTask(data, ref, eligible, today)
+		if err != nil {
+			return false, err
+		}
+		var changed bool
+		message, changed, err = change(data, r)
+		return changed, err
+	})
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(out, message)
+	return 0
+}
+
+func indexOf(tasks []model.Task, id string) int {
+	for i, task := range tasks {
+		if task.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+func saveData(t *testing.T, path string, data model.TodoData) {
+	t.Helper()
+	if err := data.Save(path, model.SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadData(t *testing.T, path string) model.TodoData {
+	t.Helper()
+	data, err := model.Load(path, 0, model.Calendar{}.Today(), model.SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestDoneByIDAndPrefixSinksCompleted(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{dayKey(0): {
+		{ID: "1", Title: "Write report"},
+		{ID: "2", Title: "Call plumber"},
+		{ID: "3", Title: "Water plants"},
+	}})
+
+	for _, ref := range []string{"1", "call"} {
+		if code, out := run(t, path, "done", ref); code != 0 {
+			t.Fatalf("done %q: code = %d, output %q", ref, code, out)
+		}
+	}
+
+	if code, out := run(t, path, "done", "1"); code != 0 || !strings.Contains(out, "already completed") {
+		t.Errorf("expected completing twice to be a no-op, got code %d output %q", code, out)
+	}
+
+	got := loadData(t, path)[dayKey(0)]
+	if len(got) != 3 || got[0].ID != "3" || got[0].Completed {
+		t.Fatalf("expected the open task first, got %v", got)
+	}
+	for _, task := range got[1:] {
+		if !task.Completed {
+			t.Errorf("expected %q completed, got %v", task.Title, got)
+		}
+	}
+}
+
+func TestUndoneRaisesAboveCompletedAndRollsPastHistoryForward(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{
+		dayKey(-3): {{ID: "old", Title: "Old win", Completed: true}},
+		dayKey(0): {
+			{ID: "open", Title: "Open"},
+			{ID: "a", Title: "Finished A", Completed: true},
+			{ID: "b", Title: "Finished B", Completed: true},
+		},
+	})
+
+	if code, out := run(t, path, "undone", "finished b"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	if code, out := run(t, path, "undone", "old"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+
+	data := loadData(t, path)
+	if _, ok := data[dayKey(-3)]; ok {
+		t.Errorf("past day should not keep a reopened task, got %v", data[dayKey(-3)])
+	}
+	got := data[dayKey(0)]
+	ids := make([]string, len(got))
+	for i, task := range got {
+		ids[i] = task.ID
+	}
+	if strings.Join(ids, ",") != "open,b,old,a" {
+		t.Fatalf("order = %v, want reopened tasks above completed", ids)
+	}
+}
+
+func TestRmAndAmbiguousPrefix(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{
+		dayKey(0):       {{ID: "1", Title: "Email Alice"}, {ID: "2", Title: "Email Bob"}},
+		model.FutureKey: {{ID: "3", Title: "Someday"}},
+	})
+
+	code, out := run(t, path, "rm", "email")
+	if code != 1 || !strings.Contains(out, "matches 2 tasks") {
+		t.Fatalf("expected ambiguity error, got code %d output %q", code, out)
+	}
+	if code, out := run(t, path, "rm", "email", "bob"); code != 0 || !strings.Contains(out, "Deleted") {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	if code, out := run(t, path, "rm", "future:1"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+
+	data := loadData(t, path)
+	if got := data[dayKey(0)]; len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("expected only Email Alice left, got %v", got)
+	}
+	if len(data[model.FutureKey]) != 0 {
+		t.Errorf("expected Future emptied, got %v", data[model.FutureKey])
+	}
+}
+
+func TestMoveFollowsSchedulingRules(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{
+		dayKey(0): {
+			{ID: "1", Title: "Plan trip", DueDate: dayKey(0)},
+			{ID: "2", Title: "Book hotel"},
+		},
+		dayKey(5): {{ID: "done", Title: "Finished", Completed: true}},
+	})
+
+	if code, out := run(t, path, "move", "plan", "future"); code != 0 || !strings.Contains(out, "to Future") {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	if code, out := run(t, path, "move", "book hotel", "+5"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+
+	data := loadData(t, path)
+	future := data[model.FutureKey]
+	if len(future) != 2 {
+		t.Fatalf("expected both tasks in Future, got %v", future)
+	}
+	if future[0].ID != "1" || future[0].DueDate != "" {
+		t.Errorf("moving to Future should clear DueDate, got %+v", future[0])
+	}
+	if future[1].ID != "2" || future[1].DueDate != dayKey(5) {
+		t.Errorf("dated move should wait in Future with its date, got %+v", future[1])
+	}
+
+	if code, out := run(t, path, "move", "plan", "future"); code != 0 || !strings.Contains(out, "already") {
+		t.Errorf("expected no-op message, got code %d output %q", code, out)
+	}
+	if code, _ := run(t, path, "move", "plan", "next-week"); code != 1 {
+		t.Errorf("expected bad destination to fail, got %d", code)
+	}
+}
+
+func TestIndexCountsSurfacedFutureTasks(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{
+		model.FutureKey: {{ID: "soon", Title: "Due tomorrow", DueDate: dayKey(1)}},
+	})
+
+	if code, out := run(t, path, "done", "tomorrow:1"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	if got := loadData(t, path)[dayKey(1)]; len(got) != 1 || !got[0].Completed {
+		t.Fatalf("expected the surfaced task completed on its day, got %v", got)
+	}
+}
+
+func TestDoneSchedulesNextOccurrence(t *testing.T) {
+	path := withTempHome(t)
+	if code, out := run(t, path, "add", "Timesheet", "--repeat", "every 7 days"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	if code, _ := run(t, path, "add", "Bad", "--repeat", "fortnightly"); code != 1 {
+		t.Errorf("expected an unknown rule to be rejected, got %d", code)
+	}
+
+	code, out := run(t, path, "done", "timesheet")
+	if code != 0 || !strings.Contains(out, "next on "+dayKey(7)) {
+		t.Fatalf("expected the next date in the output, got code %d output %q", code, out)
+	}
+	future := loadData(t, path)[model.FutureKey]
+	if len(future) != 1 || future[0].DueDate != dayKey(7) || future[0].Repeat != "every 7 days" {
+		t.Fatalf("expected the next occurrence waiting in Future, got %v", future)
+	}
+}
+
+func TestDeadlineSetsAndClearsWithoutMoving(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{
+		dayKey(0): {{ID: "1", Title: "Quarterly report"}},
+	})
+
+	if code, out := run(t, path, "deadline", "quarterly", "+3"); code != 0 || !strings.Contains(out, "due by "+dayKey(3)) {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	data := loadData(t, path)
+	if task := data[dayKey(0)][0]; task.Deadline != dayKey(3) || task.DueDate != "" {
+		t.Fatalf("expected a deadline without rescheduling, got %+v", task)
+	}
+
+	if code, out := run(t, path, "deadline", "quarterly", "+3"); code != 0 || !strings.Contains(out, "already") {
+		t.Errorf("expected no-op message, got code %d output %q", code, out)
+	}
+	if code, out := run(t, path, "deadline", "quarterly", "none"); code != 0 || !strings.Contains(out, "Cleared") {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	if task := loadData(t, path)[dayKey(0)][0]; task.Deadline != "" {
+		t.Errorf("expected the deadline cleared, got %q", task.Deadline)
+	}
+
+	for _, bad := range []string{"future", "someday"} {
+		if code, _ := run(t, path, "deadline", "quarterly", bad); code != 1 {
+			t.Errorf("deadline %q: code = %d, want 1", bad, code)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+// taskRef locates one task in the data.
+type taskRef struct {
+	key  string
+	idx  int
+	task model.Task
+}
+
+// findTask resolves a task reference given on the command line. It accepts,
+// in order of precedence:
+//
+//   - date:index, where date is today, tomorrow, future, YYYY-MM-DD, or MM-DD
+//     and index is the 1-based position shown by `doitdoit list`;
+//   - an exact task ID;
+//   - a case-insensitive title prefix that matches exactly one task for which
+//     eligible returns true.
+//
+// Day names are relative to today.
+func findTask(data model.TodoData, ref string, eligible func(model.Task) bool, today time.Time) (taskRef, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return taskRef{}, fmt.Errorf("no task given")
+	}
+
+	if keyPart, idxPart, ok := strings.Cut(ref, ":"); ok {
+		if n, err := strconv.Atoi(idxPart); err == nil {
+			key, err := resolveDayKey(keyPart, today)
+			if err != nil {
+				return taskRef{}, err
+			}
+			tasks := data[key]
+			if n < 1 || n > len(tasks) {
+				return taskRef{}, fmt.Errorf("%s has no task %d", keyPart, n)
+			}
+			return taskRef{key: key, idx: n - 1, task: tasks[n-1]}, nil
+		}
+	}
+
+	keys := data.SortedKeys()
+	for _, key := range keys {
+		for i, task := range data[key] {
+			if task.ID == ref {
+				return taskRef{key: key, idx: i, task: task}, nil
+			}
+		}
+	}
+
+	prefix := strings.ToLower(ref)
+	var matches []taskRef
+	for _, key := range keys {
+		for i, task := range data[key] {
+			if eligible(task) && strings.HasPrefix(strings.ToLower(task.Title), prefix) {
+				matches = append(matches, taskRef{key: key, idx: i, task: task})
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return taskRef{}, fmt.Errorf("no task matches %q", ref)
+	case 1:
+		return matches[0], nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d tasks; use an ID or date:index:", ref, len(matches))
+	for i, match := range matches {
+		if i == 5 {
+			fmt.Fprintf(&b, "\n  …and %d more", len(matches)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n  %s  %s (%s)", match.task.ID, match.task.Title, match.key)
+	}
+	return taskRef{}, fmt.Errorf("%s", b.String())
+}
+
+// resolveDayKey turns a day name from the command line into a TodoData key.
+func resolveDayKey(name string, today time.Time) (string, error) {
+	switch strings.ToLower(name) {
+	case "today":
+		return today.Format("2006-01-02"), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1).Format("2006-01-02"), nil
+	case "future":
+		return model.FutureKey, nil
+	}
+	return model.NormalizeDueDate(name, today)
+}
+
+// resolveDestination parses a move destination: today, tomorrow, future,
+// +N days from today, YYYY-MM-DD, or MM-DD. An empty date means undated
+// Future.
+func resolveDestination(name string, today time.Time) (string, error) {
+	if days, ok := strings.CutPrefix(name, "+"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid destination %q; use +N with N zero or more", name)
+		}
+		return today.AddDate(0, 0, n).Format("2006-01-02"), nil
+	}
+	key, err := resolveDayKey(name, today)
+	if err != nil {
+		return "", fmt.Errorf("invalid destination %q; use today, tomorrow, future, +N, YYYY-MM-DD, or MM-DD", name)
+	}
+	if key == model.FutureKey {
+		return "", nil
+	}
+	return key, nil
+}
+
+// resolveDeadline accepts the same days as resolveDestination, except that
+// a deadline needs a date: "future" is refused and "none" clears it.
+func resolveDeadline(name string, today time.Time) (string, error) {
+	if strings.EqualFold(name, "none") {
+		return "", nil
+	}
+	if !strings.EqualFold(name, "future") {
+		if date, err := resolveDestination(name, today); err == nil {
+			return date, nil
+		}
+	}
+	return "", fmt.Errorf("invalid deadline %q; use today, tomorrow, +N, YYYY-MM-DD, MM-DD, or none", name)
+}
+
+// distributeThrough surfaces dated Future tasks up to and including the given
+// key, as scrolling the TUI to that day would, so date:index references
+// count the same tasks `list` showed. Future and unparseable keys only apply
+// the default window.
+func distributeThrough(data model.TodoData, key string, today time.Time) {
+	days := defaultDays
+	if date, err := time.ParseInLocation("2006-01-02", key, today.Location()); err == nil {
+		// Round rather than truncate so a DST change inside the span
+		// cannot lose a day.
+		if span := int((date.Sub(today)+12*time.Hour)/(24*time.Hour)) + 1; span > days {
+			days = span
+		}
+	}
+	data.DistributeFutureTasks(days, today)
+}
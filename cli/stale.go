@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/config"
+	"github.com/dtt101/doitdoit/model"
+)
+
+const staleUsage = "Usage: doitdoit stale [--after N] [--json]"
+
+// staleTask is one entry of the stale report. Ref is a date:index reference
+// the mutating commands accept, so each task can be acted on directly.
+type staleTask struct {
+	Ref  string     `json:"ref"`
+	Task model.Task `json:"task"`
+}
+
+// runStale lists open tasks that rollover has carried forward more than the
+// threshold, longest-drifting first, so each gets a decision.
+func runStale(args []string, e env, out, errOut io.Writer) int {
+	threshold := e.staleAfter
+	if threshold == 0 {
+		// The TUI marker is off, but the report is still asked for.
+		threshold = config.DefaultStaleAfter
+	}
+	fs := newFlagSet("stale", staleUsage, errOut)
+	after := fs.Int("after", threshold, "list tasks rolled over more than this many times")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return flagExitCode(err)
+	}
+	if len(positional) > 0 {
+		fmt.Fprintln(errOut, staleUsage)
+		return 1
+	}
+	if *after < 0 {
+		fmt.Fprintln(errOut, "--after must be zero or a positive number of rollovers.")
+		return 1
+	}
+
+	today := e.cal.Today()
+	data, err := model.Snapshot(e.path, defaultDays, today)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error loading tasks: %v\n", err)
+		return 1
+	}
+	stale := staleTasks(data, *after, today)
+
+	if *asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(stale); err != nil {
+			fmt.Fprintf(errOut, "Error encoding report: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	writeStaleReport(out, stale, *after)
+	return 0
+}
+
+// staleTasks collects the open tasks rolled over more than after times,
+// most rollovers first. It never returns nil, so an empty report encodes as
+// [] rather than null. Tasks on today's list are referred to as today:N.
+func staleTasks(data model.TodoData, after int, today time.Time) []staleTask {
+	todayKey := today.Format("2006-01-02")
+	stale := []staleTask{}
+	for key, tasks := range data {
+		prefix := key
+		switch key {
+		case todayKey:
+			prefix = "today"
+		case model.FutureKey:
+			prefix = "future"
+		}
+		for idx, task := range tasks {
+			if task.RolledOverMoreThan(after) {
+				stale = append(stale, staleTask{Ref: fmt.Sprintf("%s:%d", prefix, idx+1), Task: task})
+			}
+		}
+	}
+	slices.SortFunc(stale, func(a, b staleTask) int {
+		if a.Task.Rollovers != b.Task.Rollovers {
+			return b.Task.Rollovers - a.Task.Rollovers
+		}
+		return strings.Compare(a.Task.Title, b.Task.Title)
+	})
+	return stale
+}
+
+func writeStaleReport(out io.Writer, stale []staleTask, after int) {
+	if len(stale) == 0 {
+		fmt.Fprintf(out, "No open task has rolled over more than %s.\n", times(after))
+		return
+	}
+	noun := "tasks have"
+	if len(stale) == 1 {
+		noun = "task has"
+	}
+	fmt.Fprintf(out, "%d %s rolled over more than %s:\n", len(stale), noun, times(after))
+	for _, s := range stale {
+		fmt.Fprintf(out, "  %-12s %s (rolled %s", s.Ref, s.Task.Title, times(s.Task.Rollovers))
+		if s.Task.FirstScheduled != "" {
+			fmt.Fprintf(out, ", first planned for %s", s.Task.FirstScheduled)
+		}
+		fmt.Fprintln(out, ")")
+	}
+	fmt.Fprintln(out, "\nDecide on each: finish it (done), give it a real date (move), or drop it (rm).")
+}
+
+// times spells out a count of rollovers: "once", "2 times".
+func times(n int) string {
+	if n == 1 {
+		return "once"
+	}
+	return fmt.Sprintf("%d times", n)
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+func TestStaleReportsDriftingTasks(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{
+		dayKey(-2): {{ID: "1", Title: "Call the bank", Rollovers: 3, FirstScheduled: dayKey(-5)}},
+		dayKey(0): {
+			{ID: "2", Title: "Water plants"},
+			{ID: "3", Title: "Fix the gate", Rollovers: 9, FirstScheduled: dayKey(-9)},
+			{ID: "4", Title: "Old chore", Rollovers: 12, Completed: true},
+		},
+	})
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, out := run(t, path, "stale")
+	if code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	// Snapshot rolls "Call the bank" forward once more, to the end of
+	// today's list, before the report counts it.
+	want := "2 tasks have rolled over more than 3 times:\n" +
+		"  today:2      Fix the gate (rolled 9 times, first planned for " + dayKey(-9) + ")\n" +
+		"  today:4      Call the bank (rolled 4 times, first planned for " + dayKey(-5) + ")\n"
+	if !strings.HasPrefix(out, want) {
+		t.Errorf("report = %q, want prefix %q", out, want)
+	}
+	if after, err := os.ReadFile(path); err != nil || string(after) != string(before) {
+		t.Error("stale should not write the file")
+	}
+
+	code, out = run(t, path, "stale", "--after", "6", "--json")
+	if code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	var report []staleTask
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report) != 1 || report[0].Ref != "today:2" || report[0].Task.ID != "3" {
+		t.Errorf("expected only the gate, got %+v", report)
+	}
+
+	if code, out := run(t, path, "stale", "--after", "20"); code != 0 || !strings.Contains(out, "No open task") {
+		t.Errorf("expected an empty report, got code %d output %q", code, out)
+	}
+	if code, _ := run(t, path, "stale", "--after", "-1"); code != 1 {
+		t.Errorf("negative threshold: code = %d, want 1", code)
+	}
+}
+
+func TestMoveClearsStaleness(t *testing.T) {
+	path := withTempHome(t)
+	saveData(t, path, model.TodoData{
+		dayKey(0): {{ID: "1", Title: "Fix the gate", Rollovers: 9, FirstScheduled: dayKey(-9)}},
+	})
+	if code, out := run(t, path, "move", "today:1", "tomorrow"); code != 0 {
+		t.Fatalf("code = %d, output %q", code, out)
+	}
+	if code, out := run(t, path, "stale"); code != 0 || !strings.Contains(out, "No open task") {
+		t.Errorf("a moved task should no longer be stale, got %q", out)
+	}
+}
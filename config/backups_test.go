@@ -0,0 +1,43 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunCommandBackups(t *testing.T) {
+	withTempHome(t)
+	var out bytes.Buffer
+	if code := RunCommand([]string{"config", "backups"}, &out); code != 0 || !strings.Contains(out.String(), "last 10 versions plus 7 daily") {
+		t.Fatalf("default policy: code=%d output=%q", code, out.String())
+	}
+
+	for _, tc := range []struct {
+		args           []string
+		versions, days int
+	}{
+		{[]string{"25"}, 25, 7},
+		{[]string{"5", "0"}, 5, 0},
+		{[]string{"off"}, 0, 0},
+	} {
+		out.Reset()
+		if code := RunCommand(append([]string{"config", "backups"}, tc.args...), &out); code != 0 {
+			t.Fatalf("backups %v: code=%d output=%q", tc.args, code, out.String())
+		}
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if versions, days := cfg.Backups(); versions != tc.versions || days != tc.days {
+			t.Errorf("backups %v saved %d, %d; want %d, %d", tc.args, versions, days, tc.versions, tc.days)
+		}
+	}
+
+	for _, args := range [][]string{{"0"}, {"-1"}, {"ten"}, {"off", "3"}, {"1", "2", "3"}} {
+		out.Reset()
+		if code := RunCommand(append([]string{"config", "backups"}, args...), &out); code != 1 {
+			t.Errorf("backups %v: code=%d, want 1", args, code)
+		}
+	}
+}
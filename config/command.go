@@ -4,13 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dtt101/doitdoit/styles"
 )
 
-const configUsage = "Usage: doitdoit config show | move <path> | theme [name] | retention [forever|days] | omarchy-hook install|status|remove"
+const configUsage = "Usage: doitdoit config show | move <path> | theme [name] | retention [forever|days] | backups [off|versions [days]] | ics-feed [off|<path> [todos|events]] | day-starts-at [midnight|HH:MM] | timezone [system|<zone>] | stale-after [off|rollovers] | omarchy-hook install|status|remove"
 
 // RunCommand executes a `config` subcommand (args[0] is expected to be
 // "config") and returns the process exit code. All output is written to out.
@@ -28,6 +30,16 @@ func RunCommand(args []string, out io.Writer) int {
 		return runTheme(args[2:], out)
 	case "retention":
 		return runRetention(args[2:], out)
+	case "backups":
+		return runBackups(args[2:], out)
+	case "ics-feed":
+		return runICSFeed(args[2:], out)
+	case "day-starts-at":
+		return runDayStartsAt(args[2:], out)
+	case "timezone":
+		return runTimeZone(args[2:], out)
+	case "stale-after":
+		return runStaleAfter(args[2:], out)
 	case "omarchy-hook":
 		return runOmarchyHook(args[2:], out)
 	default:
@@ -49,6 +61,11 @@ func runShow(out io.Writer) int {
 	}
 	fmt.Fprintf(out, "Theme: %s\n", theme)
 	fmt.Fprintf(out, "Retention: %s\n", retentionDescription(cfg))
+	fmt.Fprintf(out, "Backups: %s\n", backupsDescription(cfg))
+	fmt.Fprintf(out, "Calendar feed: %s\n", icsFeedDescription(cfg))
+	fmt.Fprintf(out, "Day starts at: %s\n", dayStartDescription(cfg))
+	fmt.Fprintf(out, "Time zone: %s\n", timeZoneDescription(cfg))
+	fmt.Fprintf(out, "Stale after: %s\n", staleAfterDescription(cfg))
 	return 0
 }
 
@@ -95,6 +112,243 @@ func runRetention(args []string, out io.Writer) int {
 	return 0
 }
 
+func backupsDescription(cfg *Config) string {
+	versions, days := cfg.Backups()
+	if versions == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("last %d versions plus %d daily snapshots", versions, days)
+}
+
+func runBackups(args []string, out io.Writer) int {
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintf(out, "Error loading config: %v\n", err)
+		return 1
+	}
+	if len(args) == 0 {
+		fmt.Fprintf(out, "Backups: %s\n", backupsDescription(cfg))
+		return 0
+	}
+	if len(args) > 2 {
+		fmt.Fprintln(out, "Usage: doitdoit config backups [off|versions [days]]")
+		return 1
+	}
+
+	_, days := cfg.Backups()
+	versions := 0
+	if strings.ToLower(args[0]) == "off" {
+		if len(args) > 1 {
+			fmt.Fprintln(out, "Usage: doitdoit config backups [off|versions [days]]")
+			return 1
+		}
+	} else {
+		counts := make([]int, len(args))
+		for i, arg := range args {
+			counts[i], err = strconv.Atoi(arg)
+			if err != nil || counts[i] < 0 || (i == 0 && counts[i] == 0) {
+				fmt.Fprintln(out, "Backups must be 'off' or a positive number of versions, optionally followed by a number of days.")
+				return 1
+			}
+		}
+		versions = counts[0]
+		if len(counts) == 2 {
+			days = counts[1]
+		}
+	}
+	cfg.SetBackups(versions, days)
+	if err := SaveConfig(cfg); err != nil {
+		fmt.Fprintf(out, "Error saving config: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "Backups set to: %s\n", backupsDescription(cfg))
+	return 0
+}
+
+func icsFeedDescription(cfg *Config) string {
+	if cfg.ICSFeed == "" {
+		return "off"
+	}
+	if cfg.ICSFeedEvents {
+		return cfg.ICSFeed + " (all-day events)"
+	}
+	return cfg.ICSFeed + " (to-dos)"
+}
+
+func runICSFeed(args []string, out io.Writer) int {
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintf(out, "Error loading config: %v\n", err)
+		return 1
+	}
+	if len(args) == 0 {
+		fmt.Fprintf(out, "Calendar feed: %s\n", icsFeedDescription(cfg))
+		return 0
+	}
+	usage := "Usage: doitdoit config ics-feed [off|<path> [todos|events]]"
+	if len(args) > 2 || (len(args) == 2 && args[1] != "todos" && args[1] != "events") {
+		fmt.Fprintln(out, usage)
+		return 1
+	}
+
+	if strings.ToLower(args[0]) == "off" {
+		if len(args) > 1 {
+			fmt.Fprintln(out, usage)
+			return 1
+		}
+		cfg.ICSFeed, cfg.ICSFeedEvents = "", false
+	} else {
+		path, err := ExpandPath(args[0])
+		if err == nil {
+			path, err = filepath.Abs(path)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "Error expanding path: %v\n", err)
+			return 1
+		}
+		if storage, _ := ExpandPath(cfg.StoragePath); SamePath(path, storage) {
+			fmt.Fprintln(out, "The calendar feed cannot replace the task file.")
+			return 1
+		}
+		cfg.ICSFeed, cfg.ICSFeedEvents = path, len(args) == 2 && args[1] == "events"
+	}
+	if err := SaveConfig(cfg); err != nil {
+		fmt.Fprintf(out, "Error saving config: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "Calendar feed set to: %s\n", icsFeedDescription(cfg))
+	return 0
+}
+
+func dayStartDescription(cfg *Config) string {
+	offset := cfg.DayStart()
+	if offset == 0 {
+		return "midnight"
+	}
+	return fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+}
+
+func runDayStartsAt(args []string, out io.Writer) int {
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintf(out, "Error loading config: %v\n", err)
+		return 1
+	}
+	if len(args) == 0 {
+		fmt.Fprintf(out, "Day starts at: %s\n", dayStartDescription(cfg))
+		return 0
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(out, "Usage: doitdoit config day-starts-at [midnight|HH:MM]")
+		return 1
+	}
+
+	offset, err := ParseDayStart(args[0])
+	if err != nil {
+		fmt.Fprintf(out, "Day start must be 'midnight' or a time of day: %v\n", err)
+		return 1
+	}
+	cfg.DayStartsAt = ""
+	if offset != 0 {
+		cfg.DayStartsAt = fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+	}
+	if err := SaveConfig(cfg); err != nil {
+		fmt.Fprintf(out, "Error saving config: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "Day starts at: %s\n", dayStartDescription(cfg))
+	return 0
+}
+
+// timeZoneDescription names the effective zone and its current offset, so a
+// traveller can see which clock "today" follows.
+func timeZoneDescription(cfg *Config) string {
+	loc, err := cfg.Location()
+	if err != nil {
+		return fmt.Sprintf("%s (%v)", cfg.TimeZone, err)
+	}
+	now := time.Now().In(loc)
+	if cfg.TimeZone == "" {
+		return fmt.Sprintf("system (currently %s, UTC%s)", now.Format("MST"), now.Format("-07:00"))
+	}
+	return fmt.Sprintf("%s (currently %s, UTC%s)", loc, now.Format("MST"), now.Format("-07:00"))
+}
+
+func runTimeZone(args []string, out io.Writer) int {
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintf(out, "Error loading config: %v\n", err)
+		return 1
+	}
+	if len(args) == 0 {
+		fmt.Fprintf(out, "Time zone: %s\n", timeZoneDescription(cfg))
+		return 0
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(out, "Usage: doitdoit config timezone [system|<zone>]")
+		return 1
+	}
+
+	cfg.TimeZone = ""
+	if !strings.EqualFold(args[0], "system") {
+		loc, err := LoadTimeZone(args[0])
+		if err != nil {
+			fmt.Fprintf(out, "Error: %v\n", err)
+			return 1
+		}
+		cfg.TimeZone = loc.String()
+	}
+	if err := SaveConfig(cfg); err != nil {
+		fmt.Fprintf(out, "Error saving config: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "Time zone set to: %s\n", timeZoneDescription(cfg))
+	return 0
+}
+
+func staleAfterDescription(cfg *Config) string {
+	switch n := cfg.Staleness(); n {
+	case 0:
+		return "off"
+	case 1:
+		return "more than 1 rollover"
+	default:
+		return fmt.Sprintf("more than %d rollovers", n)
+	}
+}
+
+func runStaleAfter(args []string, out io.Writer) int {
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintf(out, "Error loading config: %v\n", err)
+		return 1
+	}
+	if len(args) == 0 {
+		fmt.Fprintf(out, "Stale after: %s\n", staleAfterDescription(cfg))
+		return 0
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(out, "Usage: doitdoit config stale-after [off|rollovers]")
+		return 1
+	}
+
+	rollovers := 0
+	if strings.ToLower(args[0]) != "off" {
+		rollovers, err = strconv.Atoi(args[0])
+		if err != nil || rollovers <= 0 {
+			fmt.Fprintln(out, "Stale after must be 'off' or a positive number of rollovers.")
+			return 1
+		}
+	}
+	cfg.SetStaleness(rollovers)
+	if err := SaveConfig(cfg); err != nil {
+		fmt.Fprintf(out, "Error saving config: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "Stale after set to: %s\n", staleAfterDescription(cfg))
+	return 0
+}
+
 func runTheme(args []string, out io.Writer) int {
 	cfg, err := LoadConfig()
 	if err != nil {
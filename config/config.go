@@ -5,15 +5,45 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
 	StoragePath   string `json:"storage_path"`
 	Theme         string `json:"theme,omitempty"`
 	RetentionDays *int   `json:"retention_days,omitempty"`
+	// BackupVersions and BackupDays override the rolling backup policy;
+	// zero versions turns backups off.
+	BackupVersions *int `json:"backup_versions,omitempty"`
+	BackupDays     *int `json:"backup_days,omitempty"`
+	// ICSFeed is a calendar file rewritten on every save; empty
+	// means no feed. ICSFeedEvents writes events rather than to-dos.
+	ICSFeed       string `json:"ics_feed,omitempty"`
+	ICSFeedEvents bool   `json:"ics_feed_events,omitempty"`
+	// DayStartsAt is the "HH:MM" a new day begins at, for people who work
+	// past midnight; empty means midnight.
+	DayStartsAt string `json:"day_starts_at,omitempty"`
+	// TimeZone is the IANA name of the home time zone dates are reckoned
+	// in, such as "Europe/London"; empty means the system zone.
+	TimeZone string `json:"time_zone,omitempty"`
+	// StaleAfter is how many rollovers a task may take before it is marked
+	// stale; zero turns the marker off.
+	StaleAfter *int `json:"stale_after,omitempty"`
 }
 
+// Default rolling backup policy: the last ten versions of the task file plus
+// the first of each of the last seven days.
+const (
+	DefaultBackupVersions = 10
+	DefaultBackupDays     = 7
+)
+
+// DefaultStaleAfter is how many rollovers a task may take before it is
+// marked stale when the config does not say.
+const DefaultStaleAfter = 3
+
 func GetConfigPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -22,7 +52,22 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(home, ".doitdoit_config.json"), nil
 }
 
+// LoadConfig reads the config file, refusing one with any setting out of
+// range. A missing file is an empty config.
 func LoadConfig() (*Config, error) {
+	cfg, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if problems := cfg.Problems(); len(problems) > 0 {
+		return nil, problems[0]
+	}
+	return cfg, nil
+}
+
+// ReadConfig reads the config file without checking its settings, for
+// callers such as doctor that report bad settings rather than stop at them.
+func ReadConfig() (*Config, error) {
 	path, err := GetConfigPath()
 	if err != nil {
 		return nil, err
@@ -40,13 +85,30 @@ func LoadConfig() (*Config, error) {
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
-	if cfg.RetentionDays != nil && *cfg.RetentionDays < 0 {
-		return nil, fmt.Errorf("retention_days must be zero or a positive integer")
-	}
-
 	return &cfg, nil
 }
 
+// Problems lists the settings that are out of range.
+func (c *Config) Problems() []error {
+	var problems []error
+	if c.RetentionDays != nil && *c.RetentionDays < 0 {
+		problems = append(problems, fmt.Errorf("retention_days must be zero or a positive integer"))
+	}
+	if (c.BackupVersions != nil && *c.BackupVersions < 0) || (c.BackupDays != nil && *c.BackupDays < 0) {
+		problems = append(problems, fmt.Errorf("backup_versions and backup_days must be zero or positive integers"))
+	}
+	if c.StaleAfter != nil && *c.StaleAfter < 0 {
+		problems = append(problems, fmt.Errorf("stale_after must be zero or a positive integer"))
+	}
+	if _, err := ParseDayStart(c.DayStartsAt); err != nil {
+		problems = append(problems, fmt.Errorf("day_starts_at: %w", err))
+	}
+	if _, err := c.Location(); err != nil {
+		problems = append(problems, fmt.Errorf("time_zone: %w", err))
+	}
+	return problems
+}
+
 // Retention returns the configured retention period. The boolean is false
 // when the user has not made a choice yet. Zero days means keep history
 // forever.
@@ -63,6 +125,82 @@ func (c *Config) SetRetention(days int) {
 	*c.RetentionDays = days
 }
 
+// Backups returns how many recent versions and daily snapshots of the task
+// file to keep. Zero versions means backups are off.
+func (c *Config) Backups() (versions, days int) {
+	versions, days = DefaultBackupVersions, DefaultBackupDays
+	if c.BackupVersions != nil {
+		versions = *c.BackupVersions
+	}
+	if c.BackupDays != nil {
+		days = *c.BackupDays
+	}
+	return versions, days
+}
+
+// SetBackups records an explicit backup policy.
+func (c *Config) SetBackups(versions, days int) {
+	c.BackupVersions, c.BackupDays = new(int), new(int)
+	*c.BackupVersions, *c.BackupDays = versions, days
+}
+
+// Staleness returns how many rollovers a task may take before it is marked
+// stale. Zero means never.
+func (c *Config) Staleness() int {
+	if c.StaleAfter == nil {
+		return DefaultStaleAfter
+	}
+	return *c.StaleAfter
+}
+
+// SetStaleness records an explicit staleness threshold.
+func (c *Config) SetStaleness(rollovers int) {
+	c.StaleAfter = new(int)
+	*c.StaleAfter = rollovers
+}
+
+// ParseDayStart reads an "HH:MM" time of day as the offset from midnight. An
+// empty value, "midnight", and "00:00" are all zero.
+func ParseDayStart(value string) (time.Duration, error) {
+	if value == "" || strings.EqualFold(value, "midnight") {
+		return 0, nil
+	}
+	hours, minutes, ok := strings.Cut(value, ":")
+	h, errH := strconv.Atoi(hours)
+	m, errM := strconv.Atoi(minutes)
+	if !ok || len(minutes) != 2 || errH != nil || errM != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %q; use HH:MM, such as 04:00", value)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// DayStart returns how long after midnight a new day begins.
+func (c *Config) DayStart() time.Duration {
+	offset, _ := ParseDayStart(c.DayStartsAt)
+	return offset
+}
+
+// Location returns the home time zone, or the system zone when none is set.
+func (c *Config) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.Local, nil
+	}
+	return LoadTimeZone(c.TimeZone)
+}
+
+// LoadTimeZone looks up an IANA time zone name. "Local" is refused, since
+// the point of a home zone is not to follow the system.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" || strings.EqualFold(name, "local") {
+		return nil, fmt.Errorf("%q is not a time zone name; use one such as Europe/London", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q; use an IANA name such as Europe/London", name)
+	}
+	return loc, nil
+}
+
 func SaveConfig(cfg *Config) error {
 	path, err := GetConfigPath()
 	if err != nil {
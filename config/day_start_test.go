@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDayStart(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"":         0,
+		"midnight": 0,
+		"00:00":    0,
+		"04:00":    4 * time.Hour,
+		"4:30":     4*time.Hour + 30*time.Minute,
+		"23:59":    23*time.Hour + 59*time.Minute,
+	} {
+		if got, err := ParseDayStart(value); err != nil || got != want {
+			t.Errorf("ParseDayStart(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"24:00", "4", "04:5", "04:60", "-1:00", "four"} {
+		if _, err := ParseDayStart(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestRunCommandDayStartsAt(t *testing.T) {
+	withTempHome(t)
+	var out bytes.Buffer
+	if code := RunCommand([]string{"config", "day-starts-at"}, &out); code != 0 || !strings.Contains(out.String(), "midnight") {
+		t.Fatalf("default: code=%d output=%q", code, out.String())
+	}
+
+	out.Reset()
+	if code := RunCommand([]string{"config", "day-starts-at", "4:00"}, &out); code != 0 || !strings.Contains(out.String(), "04:00") {
+		t.Fatalf("set: code=%d output=%q", code, out.String())
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DayStartsAt != "04:00" || cfg.DayStart() != 4*time.Hour {
+		t.Errorf("saved %q", cfg.DayStartsAt)
+	}
+	out.Reset()
+	RunCommand([]string{"config", "show"}, &out)
+	if !strings.Contains(out.String(), "Day starts at: 04:00") {
+		t.Errorf("show output %q", out.String())
+	}
+
+	out.Reset()
+	if code := RunCommand([]string{"config", "day-starts-at", "25:00"}, &out); code != 1 {
+		t.Errorf("invalid time: code=%d output=%q", code, out.String())
+	}
+	out.Reset()
+	if code := RunCommand([]string{"config", "day-starts-at", "midnight"}, &out); code != 0 {
+		t.Fatalf("reset: code=%d output=%q", code, out.String())
+	}
+	if cfg, _ := LoadConfig(); cfg.DayStartsAt != "" {
+		t.Errorf("midnight should clear the setting, got %q", cfg.DayStartsAt)
+	}
+}
+
+func TestLoadConfigRejectsBadDayStart(t *testing.T) {
+	home := withTempHome(t)
+	os.WriteFile(filepath.Join(home, ".doitdoit_config.json"), []byte(`{"day_starts_at": "late"}`), 0600)
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "day_starts_at") {
+		t.Errorf("expected a day_starts_at error, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCommandICSFeed(t *testing.T) {
+	home := withTempHome(t)
+	if err := SaveConfig(&Config{StoragePath: filepath.Join(home, "tasks.json")}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if code := RunCommand([]string{"config", "ics-feed", "~/cal/doitdoit.ics", "events"}, &out); code != 0 {
+		t.Fatalf("code=%d output=%q", code, out.String())
+	}
+	cfg, _ := LoadConfig()
+	if cfg.ICSFeed != filepath.Join(home, "cal", "doitdoit.ics") || !cfg.ICSFeedEvents {
+		t.Fatalf("saved feed %q events=%v", cfg.ICSFeed, cfg.ICSFeedEvents)
+	}
+
+	out.Reset()
+	RunCommand([]string{"config", "show"}, &out)
+	if !strings.Contains(out.String(), "Calendar feed: "+cfg.ICSFeed+" (all-day events)") {
+		t.Errorf("show = %q", out.String())
+	}
+
+	for _, args := range [][]string{{"~/tasks.json"}, {"feed.ics", "weekly"}, {"off", "events"}} {
+		out.Reset()
+		if code := RunCommand(append([]string{"config", "ics-feed"}, args...), &out); code != 1 {
+			t.Errorf("ics-feed %v: code=%d, want 1", args, code)
+		}
+	}
+
+	out.Reset()
+	if code := RunCommand([]string{"config", "ics-feed", "off"}, &out); code != 0 {
+		t.Fatalf("off: code=%d", code)
+	}
+	if cfg, _ := LoadConfig(); cfg.ICSFeed != "" || cfg.ICSFeedEvents {
+		t.Errorf("expected the feed turned off, got %+v", cfg)
+	}
+}
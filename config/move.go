@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+
+	"github.com/dtt101/doitdoit/lockfile"
 )
 
 // ErrOldNotRemoved means the data reached the destination but the original
@@ -32,8 +34,17 @@ func SamePath(a, b string) bool {
 // MoveStorage moves the storage file from oldPath to newPath, using an atomic
 // no-replace hard link on one filesystem and falling back to a no-replace copy
 // across filesystems. It returns ErrOldNotRemoved if the data reaches the
-// destination but the original cannot be deleted.
+// destination but the original cannot be deleted. The original's lock is held
+// throughout, so no other doitdoit process writes it mid-move; a directory
+// that cannot hold the lock file cannot be written by them either.
 func MoveStorage(oldPath, newPath string) error {
+	lock, err := lockfile.Acquire(oldPath, lockfile.DefaultTimeout)
+	switch {
+	case err == nil:
+		defer lock.Release()
+	case !errors.Is(err, os.ErrNotExist) && !errors.Is(err, os.ErrPermission):
+		return fmt.Errorf("locking current storage file: %w", err)
+	}
 	return moveStorage(oldPath, newPath, os.Link)
 }
 
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCommandStaleAfter(t *testing.T) {
+	withTempHome(t)
+	var out bytes.Buffer
+	if code := RunCommand([]string{"config", "stale-after"}, &out); code != 0 || !strings.Contains(out.String(), "more than 3 rollovers") {
+		t.Fatalf("default threshold: code=%d output=%q", code, out.String())
+	}
+
+	for _, tc := range []struct {
+		arg  string
+		want int
+	}{
+		{"7", 7},
+		{"off", 0},
+		{"1", 1},
+	} {
+		out.Reset()
+		if code := RunCommand([]string{"config", "stale-after", tc.arg}, &out); code != 0 {
+			t.Fatalf("stale-after %s: code=%d output=%q", tc.arg, code, out.String())
+		}
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cfg.Staleness(); got != tc.want {
+			t.Errorf("stale-after %s saved %d, want %d", tc.arg, got, tc.want)
+		}
+	}
+
+	for _, args := range [][]string{{"0"}, {"-2"}, {"often"}, {"3", "4"}} {
+		out.Reset()
+		if code := RunCommand(append([]string{"config", "stale-after"}, args...), &out); code != 1 {
+			t.Errorf("stale-after %v: code=%d, want 1", args, code)
+		}
+	}
+}
+
+func TestLoadConfigRejectsNegativeStaleAfter(t *testing.T) {
+	home := withTempHome(t)
+	if err := os.WriteFile(filepath.Join(home, ".doitdoit_config.json"), []byte(`{"stale_after": -1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "stale_after") {
+		t.Fatalf("expected a stale_after error, got %v", err)
+	}
+}
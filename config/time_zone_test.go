@@ -0,0 +1,56 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCommandTimeZone(t *testing.T) {
+	withTempHome(t)
+	var out bytes.Buffer
+	if code := RunCommand([]string{"config", "timezone"}, &out); code != 0 || !strings.Contains(out.String(), "Time zone: system (currently ") {
+		t.Fatalf("default: code=%d output=%q", code, out.String())
+	}
+
+	out.Reset()
+	if code := RunCommand([]string{"config", "timezone", "UTC"}, &out); code != 0 || !strings.Contains(out.String(), "UTC (currently UTC, UTC+00:00)") {
+		t.Fatalf("set: code=%d output=%q", code, out.String())
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc, err := cfg.Location(); err != nil || loc.String() != "UTC" {
+		t.Errorf("saved zone %q gives %v, %v", cfg.TimeZone, loc, err)
+	}
+	out.Reset()
+	RunCommand([]string{"config", "show"}, &out)
+	if !strings.Contains(out.String(), "Time zone: UTC (currently UTC, UTC+00:00)") {
+		t.Errorf("show output %q", out.String())
+	}
+
+	for _, bad := range []string{"Mars/Olympus_Mons", "Local"} {
+		out.Reset()
+		if code := RunCommand([]string{"config", "timezone", bad}, &out); code != 1 {
+			t.Errorf("%s: code=%d output=%q", bad, code, out.String())
+		}
+	}
+	out.Reset()
+	if code := RunCommand([]string{"config", "timezone", "system"}, &out); code != 0 {
+		t.Fatalf("reset: code=%d output=%q", code, out.String())
+	}
+	if cfg, _ := LoadConfig(); cfg.TimeZone != "" {
+		t.Errorf("system should clear the setting, got %q", cfg.TimeZone)
+	}
+}
+
+func TestLoadConfigRejectsUnknownTimeZone(t *testing.T) {
+	home := withTempHome(t)
+	os.WriteFile(filepath.Join(home, ".doitdoit_config.json"), []byte(`{"time_zone": "Nowhere/Special"}`), 0600)
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "time_zone") {
+		t.Errorf("expected a time_zone error, got %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package interchange
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+// csvFields lists the fields a CSV import fills and the header names each is
+// recognised by, case-insensitively, unless ParseOptions.Columns says
+// otherwise. Only title is required.
+var csvFields = []struct {
+	Name    string
+	Headers []string
+}{
+	{"title", []string{"title", "task", "name", "summary"}},
+	{"date", []string{"date", "due", "due_date", "due date"}},
+	{"completed", []string{"completed", "done", "status"}},
+	{"notes", []string{"notes", "note", "description"}},
+	{"created", []string{"created", "created_at", "created at"}},
+	{"repeat", []string{"repeat", "recurrence"}},
+}
+
+// CSVFields names the fields ParseCSV can map columns to.
+func CSVFields() []string {
+	names := make([]string, len(csvFields))
+	for i, field := range csvFields {
+		names[i] = field.Name
+	}
+	return names
+}
+
+// ParseCSV reads tasks from CSV whose first row is a header. columns maps a
+// field to the header of the column holding it, for files whose headers are
+// not among the recognised names. Dates are YYYY-MM-DD in the home time
+// zone loc; created may also be an RFC 3339 timestamp; completed is true,
+// yes, x, 1, or done.
+func ParseCSV(r io.Reader, columns map[string]string, loc *time.Location) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := csvColumns(header, columns)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		entry, err := csvEntry(field, loc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// csvColumns finds each field's column in header.
+func csvColumns(header []string, columns map[string]string) (map[string]int, error) {
+	for name := range columns {
+		known := false
+		for _, field := range csvFields {
+			known = known || field.Name == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %q; use one of %s", name, strings.Join(CSVFields(), ", "))
+		}
+	}
+
+	index := make(map[string]int)
+	for _, field := range csvFields {
+		headers := field.Headers
+		if mapped, ok := columns[field.Name]; ok {
+			headers = []string{mapped}
+		}
+		for i, name := range header {
+			for _, want := range headers {
+				if _, found := index[field.Name]; !found && strings.EqualFold(strings.TrimSpace(name), want) {
+					index[field.Name] = i
+				}
+			}
+		}
+		if _, ok := columns[field.Name]; ok {
+			if _, found := index[field.Name]; !found {
+				return nil, fmt.Errorf("no %q column for %s", columns[field.Name], field.Name)
+			}
+		}
+	}
+	if _, ok := index["title"]; !ok {
+		return nil, errors.New("no title column; name one with --columns title=HEADER")
+	}
+	return index, nil
+}
+
+func csvEntry(field func(string) string, loc *time.Location) (Entry, error) {
+	title := field("title")
+	if title == "" {
+		return Entry{}, errors.New("task has no title")
+	}
+	completed, err := parseCompleted(field("completed"))
+	if err != nil {
+		return Entry{}, err
+	}
+	date := field("date")
+	if date != "" {
+		if _, err := parseDay(date, loc); err != nil {
+			return Entry{}, err
+		}
+	}
+	var created time.Time
+	if value := field("created"); value != "" {
+		if created, err = time.Parse(time.RFC3339, value); err != nil {
+			if created, err = parseDay(value, loc); err != nil {
+				return Entry{}, fmt.Errorf("invalid created time %q; use YYYY-MM-DD or RFC 3339", value)
+			}
+		}
+	}
+
+	entry := newEntry(title, completed, date, created)
+	entry.Task.Notes = field("notes")
+	if rule := field("repeat"); rule != "" {
+		parsed, err := model.ParseRecurrence(rule)
+		if err != nil {
+			return Entry{}, err
+		}
+		entry.Task.Repeat = parsed.String()
+	}
+	return entry, nil
+}
+
+func parseCompleted(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "y", "x", "1", "done", "completed":
+		return true, nil
+	case "", "false", "no", "n", "0", "todo", "open":
+		return false, nil
+	default:
+		return false, fmt.Errorf("completed value %q is not yes or no", value)
+	}
+}
+
+// WriteCSV writes a header row and one row per task, with columns that
+// ParseCSV recognises plus the task's ID. Created is an RFC 3339 timestamp.
+func WriteCSV(w io.Writer, groups []Group) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "title", "date", "completed", "notes", "created", "repeat"})
+	for _, group := range groups {
+		for _, task := range group.Tasks {
+			created := ""
+			if !task.CreatedAt.IsZero() {
+				created = task.CreatedAt.Format(time.RFC3339)
+			}
+			writer.Write([]string{task.ID, task.Title, group.Date, strconv.FormatBool(task.Completed), task.Notes, created, task.Repeat})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,50 @@
+package interchange
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSVRecognisesHeaders(t *testing.T) {
+	input := "Task,Due Date,Done,Description,Created,Repeat\n" +
+		"Pay rent,2026-11-01,no,\"Standing order\nfailed\",2026-10-01T09:30:00Z,monthly on 1\n" +
+		",,,,,\n" +
+		"Filed taxes,2026-04-30,yes,,2026-04-01,\n"
+	entries, err := ParseCSV(strings.NewReader(input), nil, time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	rent, taxes := entries[0], entries[1]
+	if rent.Task.Title != "Pay rent" || rent.Date != "2026-11-01" || rent.Task.Completed ||
+		rent.Task.Notes != "Standing order\nfailed" || rent.Task.Repeat != "monthly on 1" ||
+		!rent.Task.CreatedAt.Equal(time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("rent = %+v", rent)
+	}
+	if !taxes.Task.Completed || taxes.Date != "2026-04-30" {
+		t.Errorf("taxes = %+v", taxes)
+	}
+}
+
+func TestParseCSVColumnMapping(t *testing.T) {
+	input := "What,When\nBuy milk,2026-10-20\n"
+	if _, err := ParseCSV(strings.NewReader(input), nil, time.Local); err == nil || !strings.Contains(err.Error(), "--columns") {
+		t.Fatalf("expected a missing title column error, got %v", err)
+	}
+	entries, err := ParseCSV(strings.NewReader(input), map[string]string{"title": "what", "date": "When"}, time.Local)
+	if err != nil || len(entries) != 1 || entries[0].Task.Title != "Buy milk" || entries[0].Date != "2026-10-20" {
+		t.Fatalf("mapped columns = %+v, %v", entries, err)
+	}
+
+	for _, columns := range []map[string]string{{"priority": "What"}, {"title": "Missing"}} {
+		if _, err := ParseCSV(strings.NewReader(input), columns, time.Local); err == nil {
+			t.Errorf("expected %v to fail", columns)
+		}
+	}
+	if _, err := ParseCSV(strings.NewReader("title,done\nOops,maybe\n"), nil, time.Local); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected a line-numbered error, got %v", err)
+	}
+}
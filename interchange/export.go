@@ -0,0 +1,123 @@
+package interchange
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+	"github.com/dtt101/doitdoit/styles"
+)
+
+// ExportFormats names the formats Export writes.
+var ExportFormats = []string{"markdown", "todotxt", "csv", "html", "ics"}
+
+// Filter selects the tasks to export. Zero values select everything.
+type Filter struct {
+	// From and To bound the tasks' days, inclusively, as YYYY-MM-DD.
+	// Setting either leaves out undated tasks.
+	From, To string
+	Tag      string
+	Project  string
+	// State is "open" or "completed" to export only those tasks.
+	State string
+}
+
+func (f Filter) keep(date string, task model.Task) bool {
+	if (f.From != "" || f.To != "") && date == "" {
+		return false
+	}
+	if (f.From != "" && date < f.From) || (f.To != "" && date > f.To) {
+		return false
+	}
+	if (f.State == "open" && task.Completed) || (f.State == "completed" && !task.Completed) {
+		return false
+	}
+	if f.Tag != "" && !task.HasTag(f.Tag) {
+		return false
+	}
+	return f.Project == "" || task.HasProject(f.Project)
+}
+
+// Group is the exported tasks of one day, or the undated tasks when Date is
+// empty.
+type Group struct {
+	Date  string
+	Tasks []model.Task
+}
+
+// Label names a group for headings, such as "2026-10-16 (Friday)".
+func (g Group) Label() string {
+	if g.Date == "" {
+		return model.FutureKey
+	}
+	day, err := parseDay(g.Date, time.UTC)
+	if err != nil {
+		return g.Date
+	}
+	return fmt.Sprintf("%s (%s)", g.Date, day.Weekday())
+}
+
+// Select groups the tasks in data that pass filter by day, in date order with
+// undated tasks last. A dated task waiting in Future is grouped under its due
+// date, and each list keeps its own order.
+func Select(data model.TodoData, filter Filter) []Group {
+	byDate := make(map[string][]model.Task)
+	for _, key := range data.SortedKeys() {
+		for _, task := range data[key] {
+			date := key
+			if key == model.FutureKey {
+				date = task.DueDate
+			}
+			if filter.keep(date, task) {
+				byDate[date] = append(byDate[date], task)
+			}
+		}
+	}
+
+	groups := make([]Group, 0, len(byDate))
+	for date, tasks := range byDate {
+		groups = append(groups, Group{Date: date, Tasks: tasks})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Date == "") != (groups[j].Date == "") {
+			return groups[j].Date == ""
+		}
+		return groups[i].Date < groups[j].Date
+	})
+	return groups
+}
+
+// ExportOptions carries what some formats need beyond the tasks.
+type ExportOptions struct {
+	// Theme colours HTML reports.
+	Theme styles.Theme
+	// Exported is when the export was taken, shown in reports and stamped
+	// on calendar entries.
+	Exported time.Time
+	// Location is the home time zone creation dates are written in; nil
+	// means time.Local.
+	Location *time.Location
+	// Events exports calendar entries as events rather than to-dos.
+	Events bool
+}
+
+// Export writes groups to w in format.
+func Export(w io.Writer, format string, groups []Group, opts ExportOptions) error {
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		return WriteMarkdown(w, groups)
+	case "todotxt", "todo.txt":
+		return WriteTodoTxt(w, groups, opts.Location)
+	case "csv":
+		return WriteCSV(w, groups)
+	case "html":
+		return WriteHTML(w, groups, opts.Theme, opts.Exported)
+	case "ics", "ical":
+		return WriteICS(w, groups, ICSOptions{Events: opts.Events, Stamp: opts.Exported})
+	default:
+		return fmt.Errorf("unknown format %q; use one of %s", format, strings.Join(ExportFormats, ", "))
+	}
+}
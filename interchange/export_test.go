@@ -0,0 +1,116 @@
+package interchange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+func exportFixture() model.TodoData {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.Local)
+	data := model.TodoData{
+		"2026-10-14": {
+			{ID: "1", Title: "Ship release +launch #priority-a", CreatedAt: created},
+			{ID: "2", Title: "Write retro #team", Completed: true, CreatedAt: created, Notes: "Went well\nMore tests"},
+		},
+		"2026-10-15": {{ID: "3", Title: "Plan sprint", CreatedAt: created}},
+		model.FutureKey: {
+			{ID: "4", Title: "Learn the cello"},
+			{ID: "5", Title: "Dentist", DueDate: "2026-11-02"},
+		},
+	}
+	for _, tasks := range data {
+		for i := range tasks {
+			tasks[i].SyncLabels()
+		}
+	}
+	return data
+}
+
+func TestSelectGroupsAndFilters(t *testing.T) {
+	data := exportFixture()
+
+	describe := func(groups []Group) string {
+		var parts []string
+		for _, group := range groups {
+			var ids []string
+			for _, task := range group.Tasks {
+				ids = append(ids, task.ID)
+			}
+			parts = append(parts, group.Label()+"="+strings.Join(ids, ","))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	for _, tc := range []struct {
+		filter Filter
+		want   string
+	}{
+		{Filter{}, "2026-10-14 (Wednesday)=1,2 2026-10-15 (Thursday)=3 2026-11-02 (Monday)=5 Future=4"},
+		{Filter{From: "2026-10-15", To: "2026-10-31"}, "2026-10-15 (Thursday)=3"},
+		{Filter{State: "completed"}, "2026-10-14 (Wednesday)=2"},
+		{Filter{State: "open", Project: "launch"}, "2026-10-14 (Wednesday)=1"},
+		{Filter{Tag: "#TEAM"}, "2026-10-14 (Wednesday)=2"},
+	} {
+		if got := describe(Select(data, tc.filter)); got != tc.want {
+			t.Errorf("%+v: got %s, want %s", tc.filter, got, tc.want)
+		}
+	}
+}
+
+// roundTrip exports the fixture in format and parses it back.
+func roundTrip(t *testing.T, format string, parse func(*bytes.Buffer) ([]Entry, error)) ([]Entry, string) {
+	t.Helper()
+	var out bytes.Buffer
+	if err := Export(&out, format, Select(exportFixture(), Filter{}), ExportOptions{Exported: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	entries, err := parse(&out)
+	if err != nil {
+		t.Fatalf("parsing exported %s: %v\n%s", format, err, text)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("round trip kept %d of 5 tasks:\n%s", len(entries), text)
+	}
+	return entries, text
+}
+
+func TestMarkdownExportRoundTrips(t *testing.T) {
+	entries, text := roundTrip(t, "markdown", func(b *bytes.Buffer) ([]Entry, error) { return ParseMarkdown(b) })
+	if !strings.Contains(text, "## 2026-10-14 (Wednesday)\n\n- [ ] Ship release +launch #priority-a\n- [x] Write retro #team\n  Went well\n  More tests\n") {
+		t.Errorf("unexpected markdown:\n%s", text)
+	}
+	if entries[1].Task.Notes != "Went well\nMore tests" || entries[3].Date != "2026-11-02" || entries[4].Date != "" {
+		t.Errorf("round trip lost detail: %+v", entries)
+	}
+}
+
+func TestTodoTxtExportRoundTrips(t *testing.T) {
+	entries, text := roundTrip(t, "todotxt", func(b *bytes.Buffer) ([]Entry, error) { return ParseTodoTxt(b, time.Local) })
+	for _, want := range []string{
+		"(A) 2026-10-01 Ship release +launch due:2026-10-14\n",
+		"x 2026-10-14 2026-10-01 Write retro @team\n",
+		"Dentist due:2026-11-02\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if entries[0].Task.Title != "Ship release +launch #priority-a" || !entries[1].Task.Completed || entries[1].Date != "2026-10-14" {
+		t.Errorf("round trip lost detail: %+v", entries)
+	}
+}
+
+func TestCSVExportRoundTrips(t *testing.T) {
+	entries, text := roundTrip(t, "csv", func(b *bytes.Buffer) ([]Entry, error) { return ParseCSV(b, nil, time.Local) })
+	if !strings.HasPrefix(text, "id,title,date,completed,notes,created,repeat\n1,") {
+		t.Errorf("unexpected csv:\n%s", text)
+	}
+	if entries[1].Task.Notes != "Went well\nMore tests" || !entries[1].Task.CreatedAt.Equal(time.Date(2026, 10, 1, 9, 0, 0, 0, time.Local)) {
+		t.Errorf("round trip lost detail: %+v", entries[1])
+	}
+}
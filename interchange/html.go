@@ -0,0 +1,139 @@
+package interchange
+
+import (
+	"fmt"
+	"html/template"
+	"image/color"
+	"io"
+	"time"
+
+	"charm.land/lipgloss/v2/compat"
+	"github.com/dtt101/doitdoit/model"
+	"github.com/dtt101/doitdoit/styles"
+)
+
+// htmlPage is a self-contained report: no scripts, fonts, or external
+// stylesheets, so it can be mailed, archived, or pasted as it is.
+var htmlPage = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>doitdoit tasks</title>
+<style>
+:root {
+{{- range .Colors}}
+  --{{.Name}}: {{.Light}};
+{{- end}}
+}
+@media (prefers-color-scheme: dark) {
+  :root {
+{{- range .Colors}}
+    --{{.Name}}: {{.Dark}};
+{{- end}}
+  }
+}
+body { margin: 2rem auto; max-width: 42rem; padding: 0 1rem; font-family: system-ui, sans-serif; line-height: 1.5; color: var(--text); background: var(--background); }
+h1 { color: var(--highlight); font-size: 1.5rem; margin-bottom: 0; }
+.exported { color: var(--subtle); margin-top: 0; }
+h2 { color: var(--special); font-size: 1.1rem; border-bottom: 1px solid var(--border); padding-bottom: 0.25rem; }
+ul { list-style: none; padding-left: 0; }
+li { margin: 0.35rem 0; }
+.box { display: inline-block; width: 1.5em; color: var(--highlight); }
+.done, .done .box { color: var(--subtle); }
+.done .title { text-decoration: line-through; }
+.label { color: var(--tag); }
+.notes { margin: 0.15rem 0 0 1.5em; color: var(--subtle); white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>Tasks</h1>
+<p class="exported">Exported {{.Exported}}</p>
+{{- range .Groups}}
+<h2>{{.Label}}</h2>
+<ul>
+{{- range .Tasks}}
+<li{{if .Completed}} class="done"{{end}}><span class="box">{{if .Completed}}&#x2611;{{else}}&#x2610;{{end}}</span><span class="title">
+{{- range .Spans}}{{if .Label}}<span class="label">{{.Text}}</span>{{else}}{{.Text}}{{end}}{{end -}}
+</span>{{if .Notes}}<div class="notes">{{.Notes}}</div>{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if not .Groups}}
+<p>No tasks.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlColor struct {
+	Name, Light, Dark string
+}
+
+type htmlTask struct {
+	Completed bool
+	Spans     []model.LabelSpan
+	Notes     string
+}
+
+type htmlGroup struct {
+	Label string
+	Tasks []htmlTask
+}
+
+// WriteHTML writes groups as a standalone page coloured from theme, following
+// the reader's light or dark preference where the theme adapts to both.
+func WriteHTML(w io.Writer, groups []Group, theme styles.Theme, exported time.Time) error {
+	page := struct {
+		Colors   []htmlColor
+		Exported string
+		Groups   []htmlGroup
+	}{Exported: exported.Format("Monday 2 January 2006 15:04")}
+
+	for _, role := range []struct {
+		name  string
+		color color.Color
+	}{
+		{"text", theme.Text},
+		{"subtle", theme.Subtle},
+		{"border", theme.Border},
+		{"highlight", theme.Highlight},
+		{"special", theme.Special},
+		{"tag", theme.Tag},
+		{"background", theme.Background},
+	} {
+		light, dark := cssColors(role.color)
+		page.Colors = append(page.Colors, htmlColor{Name: role.name, Light: light, Dark: dark})
+	}
+
+	for _, group := range groups {
+		out := htmlGroup{Label: group.Label()}
+		for _, task := range group.Tasks {
+			out.Tasks = append(out.Tasks, htmlTask{Completed: task.Completed, Spans: model.SplitLabels(task.Title), Notes: task.Notes})
+		}
+		page.Groups = append(page.Groups, out)
+	}
+	return htmlPage.Execute(w, page)
+}
+
+// cssColors renders a theme colour for light and dark pages. Adaptive colours
+// differ between the two; anything else is the same in both, and a missing
+// colour is left to the browser.
+func cssColors(c color.Color) (light, dark string) {
+	switch c := c.(type) {
+	case nil:
+		return "inherit", "inherit"
+	case compat.AdaptiveColor:
+		return cssHex(c.Light), cssHex(c.Dark)
+	default:
+		return cssHex(c), cssHex(c)
+	}
+}
+
+func cssHex(c color.Color) string {
+	if c == nil {
+		return "inherit"
+	}
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
@@ -0,0 +1,46 @@
+package interchange
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/dtt101/doitdoit/model"
+	"github.com/dtt101/doitdoit/styles"
+)
+
+func TestWriteHTMLIsThemedAndEscaped(t *testing.T) {
+	theme, err := styles.BuiltinTheme("catppuccin-latte")
+	if err != nil {
+		t.Fatal(err)
+	}
+	theme.Tag = lipgloss.Color("#123456")
+	groups := []Group{{Date: "2026-10-16", Tasks: []model.Task{
+		{Title: "Fix <script> tag #web", Completed: true, Notes: "See PR"},
+		{Title: "Open task"},
+	}}}
+
+	var out bytes.Buffer
+	if err := WriteHTML(&out, groups, theme, time.Date(2026, 10, 16, 17, 30, 0, 0, time.Local)); err != nil {
+		t.Fatal(err)
+	}
+	page := out.String()
+	for _, want := range []string{
+		"--tag: #123456;",
+		"--background: #eff1f5;",
+		`<h2>2026-10-16 (Friday)</h2>`,
+		`<li class="done">`,
+		`Fix &lt;script&gt; tag <span class="label">#web</span>`,
+		`<div class="notes">See PR</div>`,
+		"Exported Friday 16 October 2026 17:30",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected %q in page:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "<script>") || strings.Contains(page, "http") {
+		t.Error("the page must be self-contained and escape titles")
+	}
+}
@@ -0,0 +1,385 @@
+package interchange
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+// icsDate is RFC 5545's DATE form, and icsStamp its UTC DATE-TIME form.
+const (
+	icsDate  = "20060102"
+	icsStamp = "20060102T150405Z"
+)
+
+// ICSOptions shapes an iCalendar export.
+type ICSOptions struct {
+	// Events writes all-day VEVENTs instead of VTODOs, for calendar apps
+	// that do not show tasks.
+	Events bool
+	// Stamp is the DTSTAMP of every component, normally the export time.
+	Stamp time.Time
+}
+
+// WriteICS writes the dated tasks in groups as an RFC 5545 calendar, each
+// task a VTODO due on its day or, with opts.Events, an all-day VEVENT.
+// Undated tasks have no place on a calendar and are left out. A task's ID
+// makes a stable UID, so subscribed calendars update tasks in place.
+func WriteICS(w io.Writer, groups []Group, opts ICSOptions) error {
+	var b bytes.Buffer
+	line := func(content string) {
+		writeFolded(&b, content)
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//doitdoit//doitdoit//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:doitdoit")
+	stamp := opts.Stamp.UTC().Format(icsStamp)
+
+	for _, group := range groups {
+		day, err := parseDay(group.Date, time.UTC)
+		if group.Date == "" || err != nil {
+			continue
+		}
+		for _, task := range group.Tasks {
+			component := "VTODO"
+			if opts.Events {
+				component = "VEVENT"
+			}
+			line("BEGIN:" + component)
+			line("UID:" + taskUID(task))
+			line("DTSTAMP:" + stamp)
+			if !task.CreatedAt.IsZero() {
+				line("CREATED:" + task.CreatedAt.UTC().Format(icsStamp))
+			}
+			summary := task.Title
+			if opts.Events {
+				if task.Completed {
+					summary = "✓ " + summary
+				}
+				line("DTSTART;VALUE=DATE:" + day.Format(icsDate))
+				line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format(icsDate))
+				line("TRANSP:TRANSPARENT")
+			} else {
+				line("DUE;VALUE=DATE:" + day.Format(icsDate))
+				if task.Completed {
+					line("STATUS:COMPLETED")
+					line("PERCENT-COMPLETE:100")
+				} else {
+					line("STATUS:NEEDS-ACTION")
+				}
+			}
+			line("SUMMARY:" + icsText(summary))
+			if task.Notes != "" {
+				line("DESCRIPTION:" + icsText(task.Notes))
+			}
+			if labels := append(append([]string(nil), task.Tags...), task.Projects...); len(labels) > 0 {
+				for i, label := range labels {
+					labels[i] = icsText(label)
+				}
+				line("CATEGORIES:" + strings.Join(labels, ","))
+			}
+			line("END:" + component)
+		}
+	}
+	line("END:VCALENDAR")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// icsUID turns a task ID into a globally unique identifier.
+func icsUID(id string) string {
+	return id + "@doitdoit"
+}
+
+// taskUID is the UID a task is exported under: the one it was imported with,
+// so the calendar it came from recognises it, or else one made from its ID.
+func taskUID(task model.Task) string {
+	if task.UID != "" {
+		return task.UID
+	}
+	return icsUID(task.ID)
+}
+
+// icsText escapes a TEXT value.
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeFolded writes one content line ending in CRLF, folding it so no
+// physical line exceeds 75 octets and no UTF-8 character is split.
+func writeFolded(b *bytes.Buffer, content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = 74
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
+
+// WriteICSFeed rewrites the calendar file at path from data, atomically so a
+// calendar app polling it never reads half a file. A zero opts.Stamp means
+// now.
+func WriteICSFeed(path string, data model.TodoData, opts ICSOptions) error {
+	if opts.Stamp.IsZero() {
+		opts.Stamp = time.Now()
+	}
+	var b bytes.Buffer
+	if err := WriteICS(&b, Select(data, Filter{}), opts); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return model.WritePrivateFile(path, b.Bytes())
+}
+
+// ICSFeed returns a model.SaveOptions.AfterSave hook that rewrites the
+// calendar feed at path after every save of the task file.
+func ICSFeed(path string, opts ICSOptions) func(model.TodoData) error {
+	return func(data model.TodoData) error {
+		if err := WriteICSFeed(path, data, opts); err != nil {
+			return fmt.Errorf("updating calendar feed: %w", err)
+		}
+		return nil
+	}
+}
+
+// icsProperty is one unfolded content line.
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// ParseICS reads the VTODO components of an iCalendar file. SUMMARY becomes
+// the title, DESCRIPTION the notes, and CATEGORIES extra #tags; STATUS
+// COMPLETED (or PERCENT-COMPLETE 100) marks the task done on its COMPLETED
+// day, and DUE, or DTSTART without one, dates it. Cancelled to-dos and other
+// components, such as events, are skipped. Each entry keeps the to-do's UID
+// so Import can recognise it again. Days and floating times are read in the
+// home time zone loc.
+func ParseICS(r io.Reader, loc *time.Location) ([]Entry, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	var todo map[string]icsProperty
+	var categories []string
+	depth := 0 // components open inside the current VTODO, such as VALARM
+	begin := 0
+
+	for _, line := range unfoldICS(string(raw)) {
+		prop, ok := parseICSLine(line.Content)
+		if !ok {
+			continue
+		}
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VTODO") && todo == nil:
+			todo, categories, depth, begin = make(map[string]icsProperty), nil, 0, line.Num
+		case todo == nil:
+		case prop.Name == "BEGIN":
+			depth++
+		case prop.Name == "END" && depth > 0:
+			depth--
+		case prop.Name == "END":
+			entry, keep, err := icsEntry(todo, categories, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", begin, err)
+			}
+			if keep {
+				entries = append(entries, entry)
+			}
+			todo = nil
+		case depth > 0:
+		case prop.Name == "CATEGORIES":
+			categories = append(categories, splitICSList(prop.Value)...)
+		default:
+			todo[prop.Name] = prop
+		}
+	}
+	return entries, nil
+}
+
+// icsEntry maps one VTODO's properties onto an entry, reporting false for a
+// cancelled to-do.
+func icsEntry(todo map[string]icsProperty, categories []string, loc *time.Location) (Entry, bool, error) {
+	status := strings.ToUpper(todo["STATUS"].Value)
+	if status == "CANCELLED" {
+		return Entry{}, false, nil
+	}
+	title := strings.TrimSpace(unescapeICSText(todo["SUMMARY"].Value))
+	if title == "" {
+		return Entry{}, false, errors.New("task has no title")
+	}
+	for _, category := range categories {
+		label := strings.ToLower(strings.Join(strings.Fields(category), "-"))
+		probe := model.Task{Title: title}
+		probe.SyncLabels()
+		if label != "" && !probe.HasTag(label) && !probe.HasProject(label) {
+			title += " #" + label
+		}
+	}
+	completed := status == "COMPLETED" || strings.TrimSpace(todo["PERCENT-COMPLETE"].Value) == "100"
+
+	date := ""
+	for _, name := range []string{"DUE", "DTSTART"} {
+		if prop, ok := todo[name]; ok {
+			day, err := icsDay(prop, loc)
+			if err != nil {
+				return Entry{}, false, fmt.Errorf("%s: %w", name, err)
+			}
+			date = day
+			break
+		}
+	}
+	if prop, ok := todo["COMPLETED"]; ok && completed {
+		if day, err := icsDay(prop, loc); err == nil {
+			date = day
+		}
+	}
+	var created time.Time
+	if prop, ok := todo["CREATED"]; ok {
+		created, _ = icsTime(prop, loc)
+	}
+
+	entry := newEntry(title, completed, date, created)
+	entry.Task.Notes = strings.TrimRight(unescapeICSText(todo["DESCRIPTION"].Value), " \t\r\n")
+	entry.Task.UID = strings.TrimSpace(todo["UID"].Value)
+	return entry, true, nil
+}
+
+// icsLine is one logical content line and the physical line it starts on.
+type icsLine struct {
+	Num     int
+	Content string
+}
+
+// unfoldICS splits content into logical lines, joining folded continuations.
+func unfoldICS(content string) []icsLine {
+	var lines []icsLine
+	for i, physical := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(physical, " ") || strings.HasPrefix(physical, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1].Content += physical[1:]
+			continue
+		}
+		lines = append(lines, icsLine{Num: i + 1, Content: physical})
+	}
+	return lines
+}
+
+// parseICSLine splits "NAME;PARAM=VALUE:value", honouring quoted parameter
+// values that contain colons.
+func parseICSLine(content string) (icsProperty, bool) {
+	quoted := false
+	colon := -1
+	for i, r := range content {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return icsProperty{}, false
+	}
+	parts := strings.Split(content[:colon], ";")
+	prop := icsProperty{Name: strings.ToUpper(parts[0]), Params: make(map[string]string), Value: content[colon+1:]}
+	for _, param := range parts[1:] {
+		if name, value, ok := strings.Cut(param, "="); ok {
+			prop.Params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop, true
+}
+
+// icsDay returns the calendar day, in the home time zone loc, of a DATE or
+// DATE-TIME value.
+func icsDay(prop icsProperty, loc *time.Location) (string, error) {
+	if strings.EqualFold(prop.Params["VALUE"], "DATE") || len(strings.TrimSpace(prop.Value)) == len(icsDate) {
+		day, err := time.ParseInLocation(icsDate, strings.TrimSpace(prop.Value), loc)
+		if err != nil {
+			return "", fmt.Errorf("invalid date %q", prop.Value)
+		}
+		return day.Format(dateLayout), nil
+	}
+	t, err := icsTime(prop, loc)
+	if err != nil {
+		return "", err
+	}
+	return t.In(loc).Format(dateLayout), nil
+}
+
+// icsTime reads a DATE-TIME in UTC ("Z"), in its TZID zone, or floating in
+// the home time zone loc.
+func icsTime(prop icsProperty, loc *time.Location) (time.Time, error) {
+	value := strings.TrimSpace(prop.Value)
+	if t, err := time.Parse(icsStamp, value); err == nil {
+		return t, nil
+	}
+	location := loc
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date-time %q", prop.Value)
+	}
+	return t, nil
+}
+
+// unescapeICSText reverses icsText.
+func unescapeICSText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitICSList splits a comma-separated TEXT list, honouring escaped commas.
+func splitICSList(value string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			items = append(items, unescapeICSText(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, unescapeICSText(value[start:]))
+}
@@ -0,0 +1,186 @@
+package interchange
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+func icsFixture() []Group {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	open := model.Task{ID: "1", Title: "Call the bank, then; file it +finance", CreatedAt: created, Notes: "Ask for\nthe new card"}
+	open.SyncLabels()
+	return []Group{
+		{Date: "2026-10-16", Tasks: []model.Task{open, {ID: "2", Title: "Done already", Completed: true}}},
+		{Date: "", Tasks: []model.Task{{ID: "3", Title: "Undated"}}},
+	}
+}
+
+func TestWriteICSTodos(t *testing.T) {
+	var out bytes.Buffer
+	stamp := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	if err := WriteICS(&out, icsFixture(), ICSOptions{Stamp: stamp}); err != nil {
+		t.Fatal(err)
+	}
+	ics := out.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"BEGIN:VTODO\r\nUID:1@doitdoit\r\nDTSTAMP:20261016T120000Z\r\nCREATED:20261001T090000Z\r\nDUE;VALUE=DATE:20261016\r\nSTATUS:NEEDS-ACTION\r\n",
+		`SUMMARY:Call the bank\, then\; file it +finance`,
+		`DESCRIPTION:Ask for\nthe new card`,
+		"CATEGORIES:finance\r\n",
+		"UID:2@doitdoit\r\nDTSTAMP:20261016T120000Z\r\nDUE;VALUE=DATE:20261016\r\nSTATUS:COMPLETED\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected %q in:\n%s", want, ics)
+		}
+	}
+	if strings.Contains(ics, "Undated") {
+		t.Error("undated tasks have no place on a calendar")
+	}
+}
+
+func TestWriteICSEvents(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteICS(&out, icsFixture(), ICSOptions{Events: true}); err != nil {
+		t.Fatal(err)
+	}
+	ics := out.String()
+	for _, want := range []string{
+		"BEGIN:VEVENT\r\n",
+		"DTSTART;VALUE=DATE:20261016\r\nDTEND;VALUE=DATE:20261017\r\nTRANSP:TRANSPARENT\r\n",
+		"SUMMARY:✓ Done already\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected %q in:\n%s", want, ics)
+		}
+	}
+	if strings.Contains(ics, "VTODO") || strings.Contains(ics, "STATUS:") {
+		t.Errorf("events should not carry to-do properties:\n%s", ics)
+	}
+}
+
+func TestWriteFoldedKeepsLinesShortAndRunesWhole(t *testing.T) {
+	var b bytes.Buffer
+	content := "SUMMARY:" + strings.Repeat("é", 100)
+	writeFolded(&b, content)
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected the line folded, got %q", lines)
+	}
+	unfolded := lines[0]
+	for i, line := range lines {
+		if len(line) > 75 || !utf8.ValidString(line) {
+			t.Errorf("line %d is %d octets or splits a character: %q", i, len(line), line)
+		}
+		if i > 0 {
+			if !strings.HasPrefix(line, " ") {
+				t.Errorf("continuation line %d must start with a space", i)
+			}
+			unfolded += line[1:]
+		}
+	}
+	if unfolded != content {
+		t.Error("unfolding should restore the content")
+	}
+}
+
+func TestWriteICSFeed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cal", "doitdoit.ics")
+	data := model.TodoData{"2026-10-16": {{ID: "1", Title: "Feed me"}}}
+	if err := WriteICSFeed(path, data, ICSOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(raw), "SUMMARY:Feed me") {
+		t.Fatalf("feed = %q, %v", raw, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files left, got %v", entries)
+	}
+}
+
+const icsImportFixture = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\nUID:meeting@example.com\r\nSUMMARY:Not a to-do\r\nEND:VEVENT\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:passport@example.com\r\n" +
+	"SUMMARY:Renew passport\\, urgently\r\n" +
+	"DESCRIPTION:Photos first\\nthen the form\r\n" +
+	"DUE;VALUE=DATE:20261120\r\n" +
+	"CATEGORIES:Travel,Admin Work\r\n" +
+	"CREATED:20261001T090000Z\r\n" +
+	"BEGIN:VALARM\r\nACTION:DISPLAY\r\nDESCRIPTION:Reminder\r\nEND:VALARM\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:bank@example.com\r\n" +
+	"SUMMARY:Call the bank about a very long\r\n" +
+	"  folded summary #money\r\n" +
+	"STATUS:COMPLETED\r\n" +
+	"DUE;TZID=\"America/New_York\":20261010T230000\r\n" +
+	"COMPLETED:20261012T100000Z\r\n" +
+	"CATEGORIES:money\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VTODO\r\nUID:someday@example.com\r\nSUMMARY:Someday\r\nEND:VTODO\r\n" +
+	"BEGIN:VTODO\r\nUID:dropped@example.com\r\nSUMMARY:Dropped\r\nSTATUS:CANCELLED\r\nEND:VTODO\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	entries, err := ParseICS(strings.NewReader(icsImportFixture), time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want the three live to-dos: %+v", len(entries), entries)
+	}
+
+	passport, bank, someday := entries[0], entries[1], entries[2]
+	if passport.Task.Title != "Renew passport, urgently #travel #admin-work" || passport.Date != "2026-11-20" || passport.Task.Completed {
+		t.Errorf("passport = %+v", passport)
+	}
+	if passport.Task.Notes != "Photos first\nthen the form" || passport.Task.UID != "passport@example.com" {
+		t.Errorf("passport notes or UID lost: %+v", passport.Task)
+	}
+	if !passport.Task.HasTag("travel") || !passport.Task.CreatedAt.Equal(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("passport labels or created time lost: %+v", passport.Task)
+	}
+	if bank.Task.Title != "Call the bank about a very long folded summary #money" || !bank.Task.Completed {
+		t.Errorf("bank = %+v", bank)
+	}
+	if want := time.Date(2026, 10, 12, 10, 0, 0, 0, time.UTC).Local().Format(dateLayout); bank.Date != want {
+		t.Errorf("a completed to-do belongs on its COMPLETED day: got %s, want %s", bank.Date, want)
+	}
+	if someday.Date != "" || someday.Task.Title != "Someday" {
+		t.Errorf("someday = %+v", someday)
+	}
+}
+
+func TestParseICSRejectsBadDates(t *testing.T) {
+	_, err := ParseICS(strings.NewReader("BEGIN:VTODO\nSUMMARY:Broken\nDUE;VALUE=DATE:2026-13\nEND:VTODO\n"), time.Local)
+	if err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected a line-numbered date error, got %v", err)
+	}
+}
+
+func TestICSRoundTripKeepsUID(t *testing.T) {
+	imported := model.Task{ID: "1", Title: "From elsewhere", UID: "abc@example.com"}
+	var out bytes.Buffer
+	if err := WriteICS(&out, []Group{{Date: "2026-10-16", Tasks: []model.Task{imported, {ID: "2", Title: "Ours"}}}}, ICSOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ParseICS(&out, time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Task.UID != "abc@example.com" || entries[1].Task.UID != "2@doitdoit" {
+		t.Errorf("UIDs = %+v", entries)
+	}
+}
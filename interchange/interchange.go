@@ -0,0 +1,215 @@
+// Package interchange converts tasks to and from formats other tools use, so
+// lists can be brought into doitdoit and taken out of it.
+package interchange
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+const dateLayout = "2006-01-02"
+
+// Entry is one imported task and the YYYY-MM-DD day it belongs to, empty for
+// an undated task. A completed task's day is when it was done; an incomplete
+// one's is when it is due.
+type Entry struct {
+	Task model.Task
+	Date string
+}
+
+// ImportFormats names the formats Parse accepts.
+var ImportFormats = []string{"todotxt", "markdown", "csv", "ics"}
+
+// ParseOptions tunes parsing for formats that need it.
+type ParseOptions struct {
+	// Columns maps CSV fields (title, date, completed, notes, created,
+	// repeat) to the header names used in the file, overriding the
+	// defaults.
+	Columns map[string]string
+	// Location is the home time zone that dates and floating times are read
+	// in; nil means time.Local.
+	Location *time.Location
+}
+
+// Parse reads entries in format from r.
+func Parse(format string, r io.Reader, opts ParseOptions) ([]Entry, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	switch strings.ToLower(format) {
+	case "todotxt", "todo.txt":
+		return ParseTodoTxt(r, loc)
+	case "markdown", "md":
+		return ParseMarkdown(r)
+	case "csv":
+		return ParseCSV(r, opts.Columns, loc)
+	case "ics", "ical", "icalendar":
+		return ParseICS(r, loc)
+	default:
+		return nil, fmt.Errorf("unknown format %q; use one of %s", format, strings.Join(ImportFormats, ", "))
+	}
+}
+
+// newEntry builds an entry with a fresh task, created at created, or zero
+// when that is not known for Import to fill in.
+func newEntry(title string, completed bool, date string, created time.Time) Entry {
+	task := model.NewTask(strings.TrimSpace(title), created)
+	task.Completed = completed
+	return Entry{Task: task, Date: date}
+}
+
+// parseDay checks a YYYY-MM-DD date and returns it as midnight in loc.
+func parseDay(s string, loc *time.Location) (time.Time, error) {
+	day, err := time.ParseInLocation(dateLayout, s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q; use YYYY-MM-DD", s)
+	}
+	return day, nil
+}
+
+// Placement returns where an entry is filed, following the app's own rules
+// relative to today (midnight in the home time zone): completed tasks stay
+// on the day they were done, so imported history lands in history;
+// incomplete ones are scheduled as `doitdoit add --date` would, waiting in
+// Future until their day comes into view; undated tasks go to Future.
+func (e Entry) Placement(today time.Time) (key, dueDate string, err error) {
+	if e.Date == "" {
+		return model.FutureKey, "", nil
+	}
+	if e.Task.Completed {
+		if day, err := parseDay(e.Date, today.Location()); err == nil && !day.After(today) {
+			return e.Date, "", nil
+		}
+	}
+	return model.ScheduleKey(e.Date, today, today)
+}
+
+// Import files entries into data, giving any task whose ID is already in use
+// a new one. Completed tasks go below a list's incomplete ones, as if they
+// had been checked off there. An entry whose UID matches a task already in
+// data, one imported earlier or exported from here, updates that task instead
+// of adding a copy, so the same calendar can be imported again and again. It
+// returns the entries with their final IDs and placements, in the order
+// given. cal decides what day it is, and new tasks whose creation time the
+// source did not record are created now by it.
+func Import(data model.TodoData, entries []Entry, cal model.Calendar) ([]Placed, error) {
+	today, now := cal.Today(), cal.Now()
+	used := make(map[string]bool)
+	byUID := make(map[string]string)
+	for _, tasks := range data {
+		for _, task := range tasks {
+			used[task.ID] = true
+			byUID[icsUID(task.ID)] = task.ID
+			if task.UID != "" {
+				byUID[task.UID] = task.ID
+			}
+		}
+	}
+
+	placed := make([]Placed, 0, len(entries))
+	for _, entry := range entries {
+		key, dueDate, err := entry.Placement(today)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", entry.Task.Title, err)
+		}
+		if id, ok := byUID[entry.Task.UID]; ok && entry.Task.UID != "" {
+			if p, ok := update(data, id, entry.Task, key, dueDate); ok {
+				placed = append(placed, p)
+				continue
+			}
+		}
+
+		task := entry.Task
+		for used[task.ID] {
+			task.ID = model.NewTask("", now).ID
+		}
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		used[task.ID] = true
+		if task.UID != "" {
+			byUID[task.UID] = task.ID
+		}
+		task.DueDate = dueDate
+		file(data, key, task)
+		placed = append(placed, Placed{Task: task, Key: key, Status: Added})
+	}
+	return placed, nil
+}
+
+// update brings the task with id in line with an imported copy of it placed
+// at key, keeping what the import does not carry, such as its ID and repeat
+// rule. The task moves only when its day or completion changed, so one
+// surfaced from Future or rolled over to today stays put.
+func update(data model.TodoData, id string, imported model.Task, key, dueDate string) (Placed, bool) {
+	for oldKey, tasks := range data {
+		for idx, task := range tasks {
+			if task.ID != id {
+				continue
+			}
+			updated := task
+			updated.Title = imported.Title
+			updated.Notes = imported.Notes
+			updated.Completed = imported.Completed
+			updated.SyncLabels()
+			if updated.UID == "" && imported.UID != icsUID(task.ID) {
+				updated.UID = imported.UID
+			}
+
+			if effectiveDay(oldKey, task) == effectiveDay(key, model.Task{DueDate: dueDate}) && updated.Completed == task.Completed {
+				if reflect.DeepEqual(updated, task) {
+					return Placed{Task: task, Key: oldKey, Status: Unchanged}, true
+				}
+				tasks[idx] = updated
+				return Placed{Task: updated, Key: oldKey, Status: Updated}, true
+			}
+			data.Remove(oldKey, idx)
+			if len(data[oldKey]) == 0 {
+				delete(data, oldKey)
+			}
+			updated.DueDate = dueDate
+			file(data, key, updated)
+			return Placed{Task: updated, Key: key, Status: Updated}, true
+		}
+	}
+	return Placed{}, false
+}
+
+// effectiveDay is the day a task filed under key is for: the key itself, or
+// the due date of a task waiting in Future.
+func effectiveDay(key string, task model.Task) string {
+	if key == model.FutureKey {
+		return task.DueDate
+	}
+	return key
+}
+
+// file adds task under key, below the incomplete tasks when it is completed.
+func file(data model.TodoData, key string, task model.Task) {
+	if task.Completed {
+		data[key] = append(data[key], task)
+	} else {
+		data.Add(key, task)
+	}
+}
+
+// Import statuses, reporting what happened to each entry.
+const (
+	Added     = "added"
+	Updated   = "updated"
+	Unchanged = "unchanged"
+)
+
+// Placed is an imported task, the list it was filed in, and whether it was
+// Added, Updated, or Unchanged.
+type Placed struct {
+	Task   model.Task
+	Key    string
+	Status string
+}
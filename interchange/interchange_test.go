@@ -0,0 +1,97 @@
+package interchange
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dtt101/doitdoit/model"
+)
+
+func TestImportPlacesEntries(t *testing.T) {
+	cal := model.Calendar{Clock: model.FixedClock(time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local))}
+	data := model.TodoData{"2026-10-16": {{ID: "taken", Title: "Existing"}, {ID: "done", Title: "Finished", Completed: true}}}
+
+	clash := newEntry("Clashing ID", false, "2026-10-16", time.Time{})
+	clash.Task.ID = "taken"
+	entries := []Entry{
+		clash,
+		newEntry("Old history", true, "2025-01-05", time.Time{}),
+		newEntry("Overdue", false, "2026-10-01", time.Time{}),
+		newEntry("Next month", false, "2026-11-16", time.Time{}),
+		newEntry("Someday", false, "", time.Time{}),
+	}
+	placed, err := Import(data, entries, cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if placed[0].Task.ID == "taken" {
+		t.Error("expected a clashing ID replaced")
+	}
+	if !placed[0].Task.CreatedAt.Equal(cal.Now()) {
+		t.Errorf("CreatedAt = %v, want the calendar's now", placed[0].Task.CreatedAt)
+	}
+	if got := data["2026-10-16"]; len(got) != 4 || got[1].Title != "Clashing ID" || got[2].Title != "Overdue" || !got[3].Completed {
+		t.Errorf("today = %v, want new tasks above the completed one", got)
+	}
+	if got := data["2025-01-05"]; len(got) != 1 || got[0].Title != "Old history" {
+		t.Errorf("expected completed history kept on its day, got %v", got)
+	}
+	future := data[model.FutureKey]
+	if len(future) != 2 || future[0].DueDate != "2026-11-16" || future[1].DueDate != "" {
+		t.Errorf("future = %v", future)
+	}
+}
+
+func TestImportDeduplicatesByUID(t *testing.T) {
+	cal := model.Calendar{Clock: model.FixedClock(time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local))}
+	data := model.TodoData{"2026-10-16": {{ID: "ours", Title: "Exported from here", Repeat: "weekly"}}}
+
+	entries, err := ParseICS(strings.NewReader(icsImportFixture), time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := Import(data, entries, cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := ParseICS(strings.NewReader(icsImportFixture), time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Import(data, again, cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range second {
+		if p.Status != Unchanged || p.Task.ID != first[i].Task.ID {
+			t.Errorf("re-import of %q: status %s, ID %s; want unchanged %s", p.Task.Title, p.Status, p.Task.ID, first[i].Task.ID)
+		}
+	}
+	count := 0
+	for _, tasks := range data {
+		count += len(tasks)
+	}
+	if count != 4 {
+		t.Errorf("expected no copies after re-importing, got %d tasks: %v", count, data)
+	}
+
+	edited := newEntry("Renamed at the source", false, "2026-10-18", time.Time{})
+	edited.Task.UID = "passport@example.com"
+	own := newEntry("Exported from here, edited", true, "2026-10-16", time.Time{})
+	own.Task.UID = "ours@doitdoit"
+	placed, err := Import(data, []Entry{edited, own}, cal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if placed[0].Status != Updated || placed[0].Key != model.FutureKey || placed[0].Task.DueDate != "2026-10-18" || placed[0].Task.ID != first[0].Task.ID {
+		t.Errorf("moved to-do = %+v", placed[0])
+	}
+	if future := data[model.FutureKey]; len(future) != 2 || future[1].Title != "Renamed at the source" || future[1].Notes != "" {
+		t.Errorf("expected the to-do renamed and rescheduled, got %v", future)
+	}
+	if got := data["2026-10-16"]; len(got) != 1 || got[0].ID != "ours" || !got[0].Completed || got[0].Repeat != "weekly" || got[0].UID != "" {
+		t.Errorf("own task = %v, want it updated in place, keeping its repeat", got)
+	}
+}
@@ -0,0 +1,102 @@
+package interchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Markdown checklists are GitHub-flavoured task list items filed under
+// headings that name their day:
+//
+//	## 2026-10-16
+//	- [ ] Water the plants
+//	- [x] Send the invoice
+//	  Indented lines below an item become its notes.
+//
+//	## Future
+//	- [ ] Learn the cello
+//
+// A heading that starts with a YYYY-MM-DD date dates the items below it; any
+// other heading, and items before the first heading, are undated.
+
+var (
+	markdownHeading = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+	markdownItem    = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	markdownDate    = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+)
+
+// ParseMarkdown reads task list items from a Markdown document. Lines that
+// are neither headings, items, nor an item's indented notes are ignored.
+func ParseMarkdown(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	date := ""
+	notesIndent := -1 // indentation an item's notes must exceed, or -1
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if heading := markdownHeading.FindStringSubmatch(text); heading != nil {
+			date, notesIndent = "", -1
+			if day := markdownDate.FindString(strings.TrimSpace(heading[1])); day != "" {
+				if _, err := parseDay(day, time.UTC); err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				date = day
+			}
+			continue
+		}
+		if item := markdownItem.FindStringSubmatch(text); item != nil {
+			if strings.TrimSpace(item[3]) == "" {
+				return nil, fmt.Errorf("line %d: task has no title", line)
+			}
+			entries = append(entries, newEntry(item[3], item[2] != " ", date, time.Time{}))
+			notesIndent = len(item[1])
+			continue
+		}
+
+		trimmed := strings.TrimLeft(text, " \t")
+		if notesIndent < 0 || trimmed == "" || len(text)-len(trimmed) <= notesIndent {
+			notesIndent = -1
+			continue
+		}
+		last := &entries[len(entries)-1].Task
+		if last.Notes != "" {
+			last.Notes += "\n"
+		}
+		last.Notes += strings.TrimRight(trimmed, " \t")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// WriteMarkdown writes groups as task lists under a heading per day, in the
+// form ParseMarkdown reads back. Notes are indented below their task.
+func WriteMarkdown(w io.Writer, groups []Group) error {
+	var b strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", group.Label())
+		for _, task := range group.Tasks {
+			mark := " "
+			if task.Completed {
+				mark = "x"
+			}
+			fmt.Fprintf(&b, "- [%s] %s\n", mark, task.Title)
+			for _, line := range strings.Split(task.Notes, "\n") {
+				if strings.TrimSpace(line) != "" {
+					fmt.Fprintf(&b, "  %s\n", line)
+				}
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,53 @@
+package interchange
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	input := `# Migrated list
+
+- [ ] Undated before any day
+
+## 2026-10-16 (Friday)
+- [ ] Water the plants
+  Ferns on Tuesday.
+  Cactus monthly.
+- [x] Send the invoice
+* [X] Starred bullet
+A stray paragraph.
+
+## Future
+- [ ] Learn the cello
+- plain bullet, not a task
+`
+	entries, err := ParseMarkdown(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		mark := " "
+		if entry.Task.Completed {
+			mark = "x"
+		}
+		got = append(got, mark+" "+entry.Date+" "+entry.Task.Title)
+	}
+	want := []string{
+		"   Undated before any day",
+		"  2026-10-16 Water the plants",
+		"x 2026-10-16 Send the invoice",
+		"x 2026-10-16 Starred bullet",
+		"   Learn the cello",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if entries[1].Task.Notes != "Ferns on Tuesday.\nCactus monthly." {
+		t.Errorf("notes = %q", entries[1].Task.Notes)
+	}
+	if entries[2].Task.Notes != "" {
+		t.Errorf("an unindented paragraph is not a note, got %q", entries[2].Task.Notes)
+	}
+}
@@ -0,0 +1,176 @@
+package interchange
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// todo.txt (https://github.com/todotxt/todo.txt) puts one task per line:
+//
+//	x 2026-10-02 2026-09-30 (A) Call the bank +finance @phone due:2026-10-01
+//
+// A leading "x" marks it done, followed by its completion date and then its
+// creation date; an incomplete task may start with a priority and a creation
+// date. +project words are already doitdoit projects and @context words
+// become #tags. The priority becomes a #priority-a style tag and orders tasks
+// within their day; due: schedules the task. Other key:value words stay in
+// the title.
+
+var (
+	todoPriority = regexp.MustCompile(`^\([A-Z]\)$`)
+	todoDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// ParseTodoTxt reads tasks in todo.txt format, with dates in the home time
+// zone loc. Blank lines are skipped.
+func ParseTodoTxt(r io.Reader, loc *time.Location) ([]Entry, error) {
+	type prioritised struct {
+		entry    Entry
+		priority string
+	}
+	var tasks []prioritised
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 {
+			continue
+		}
+
+		completed := false
+		var completedOn, createdOn, priority string
+		if words[0] == "x" {
+			completed = true
+			words = words[1:]
+			if len(words) > 0 && todoDate.MatchString(words[0]) {
+				completedOn, words = words[0], words[1:]
+			}
+		} else if todoPriority.MatchString(words[0]) {
+			priority, words = words[0][1:2], words[1:]
+		}
+		if len(words) > 0 && todoDate.MatchString(words[0]) {
+			createdOn, words = words[0], words[1:]
+		}
+
+		var due string
+		title := make([]string, 0, len(words))
+		for _, word := range words {
+			switch {
+			case strings.HasPrefix(word, "due:"):
+				due = strings.TrimPrefix(word, "due:")
+			case strings.HasPrefix(word, "pri:") && len(word) == 5:
+				// Some tools move a done task's priority here.
+				priority = strings.ToUpper(word[4:])
+			case len(word) > 1 && word[0] == '@':
+				title = append(title, "#"+word[1:])
+			default:
+				title = append(title, word)
+			}
+		}
+		if priority != "" {
+			title = append(title, "#priority-"+strings.ToLower(priority))
+		}
+		if len(title) == 0 {
+			return nil, fmt.Errorf("line %d: task has no title", line)
+		}
+
+		var created time.Time
+		for _, date := range []string{due, completedOn, createdOn} {
+			if date == "" {
+				continue
+			}
+			if _, err := parseDay(date, loc); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		if createdOn != "" {
+			created, _ = parseDay(createdOn, loc)
+		}
+
+		date := due
+		if completed {
+			date = completedOn
+		}
+		tasks = append(tasks, prioritised{newEntry(strings.Join(title, " "), completed, date, created), priority})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].priority != "" && (tasks[j].priority == "" || tasks[i].priority < tasks[j].priority)
+	})
+	entries := make([]Entry, len(tasks))
+	for i, task := range tasks {
+		entries[i] = task.entry
+	}
+	return entries, nil
+}
+
+// WriteTodoTxt writes one todo.txt line per task, reversing ParseTodoTxt's
+// mapping: a #priority-a tag becomes the priority and other #tags become
+// @contexts. A completed task is marked done on its group's day, and an open
+// dated one is due then. Creation dates are written in the home time zone
+// loc, or time.Local when it is nil. todo.txt has no notes, so they are left
+// out.
+func WriteTodoTxt(w io.Writer, groups []Group, loc *time.Location) error {
+	if loc == nil {
+		loc = time.Local
+	}
+	var b strings.Builder
+	for _, group := range groups {
+		for _, task := range group.Tasks {
+			var words []string
+			priority := ""
+			for _, word := range strings.Fields(task.Title) {
+				if pri, ok := strings.CutPrefix(word, "#priority-"); ok && len(pri) == 1 && priority == "" {
+					priority = strings.ToUpper(pri)
+					continue
+				}
+				if tag, ok := strings.CutPrefix(word, "#"); ok && tag != "" {
+					word = "@" + tag
+				}
+				words = append(words, word)
+			}
+
+			var line []string
+			created := ""
+			if !task.CreatedAt.IsZero() {
+				created = task.CreatedAt.In(loc).Format(dateLayout)
+			}
+			switch {
+			case task.Completed:
+				line = append(line, "x")
+				// A creation date is only allowed after a completion date.
+				if group.Date != "" {
+					line = append(line, group.Date)
+					if created != "" {
+						line = append(line, created)
+					}
+				}
+			case priority != "":
+				line = append(line, "("+priority+")")
+				fallthrough
+			default:
+				if created != "" {
+					line = append(line, created)
+				}
+			}
+			line = append(line, words...)
+			if task.Completed && priority != "" {
+				line = append(line, "pri:"+priority)
+			}
+			if !task.Completed && group.Date != "" {
+				line = append(line, "due:"+group.Date)
+			}
+			b.WriteString(strings.Join(line, " ") + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
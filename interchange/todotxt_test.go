@@ -0,0 +1,50 @@
+package interchange
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTodoTxt(t *testing.T) {
+	input := `
+(B) 2026-09-01 Renew passport +admin @errands due:2026-11-02
+x 2026-10-02 2026-09-30 Call the bank pri:A +finance
+Read a book
+(A) Book dentist t:2026-10-10
+`
+	entries, err := ParseTodoTxt(strings.NewReader(input), time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries, want 4", len(entries))
+	}
+
+	// Priorities come first, A before B, then the rest in file order.
+	bank, book, passport, read := entries[0], entries[1], entries[2], entries[3]
+	if book.Task.Title != "Book dentist t:2026-10-10 #priority-a" || book.Date != "" {
+		t.Errorf("book = %+v", book)
+	}
+	if passport.Task.Title != "Renew passport +admin #errands #priority-b" || passport.Date != "2026-11-02" {
+		t.Errorf("passport = %+v", passport)
+	}
+	if !passport.Task.HasTag("errands") || !passport.Task.HasProject("admin") {
+		t.Errorf("expected labels synced, got %v %v", passport.Task.Tags, passport.Task.Projects)
+	}
+	if want := time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local); !passport.Task.CreatedAt.Equal(want) {
+		t.Errorf("created = %v, want %v", passport.Task.CreatedAt, want)
+	}
+	if !bank.Task.Completed || bank.Date != "2026-10-02" || bank.Task.Title != "Call the bank +finance #priority-a" {
+		t.Errorf("bank = %+v", bank)
+	}
+	if read.Task.Completed || read.Date != "" || read.Task.ID == "" {
+		t.Errorf("read = %+v", read)
+	}
+}
+
+func TestParseTodoTxtRejectsBadDates(t *testing.T) {
+	if _, err := ParseTodoTxt(strings.NewReader("Pay rent due:2026-02-30\n"), time.Local); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected a line-numbered date error, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+// Package lockfile provides an advisory lock shared by every doitdoit process
+// that writes the task file: a lock file beside it, created exclusively and
+// removed on release. A lock left behind by a crashed process is detected as
+// stale and broken, so a crash never wedges later writers.
+package lockfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout is how long writers wait for a busy lock.
+	DefaultTimeout = 5 * time.Second
+	// StaleAfter is the age past which a lock is assumed abandoned. Holders
+	// only keep the lock for a single load-change-save, well under a second.
+	StaleAfter = 30 * time.Second
+	// pollInterval is how often a waiting writer retries.
+	pollInterval = 25 * time.Millisecond
+)
+
+// ErrTimeout means another process held the lock for the whole wait.
+var ErrTimeout = errors.New("timed out waiting for the task file lock")
+
+// Lock is a held lock. Release it exactly once.
+type Lock struct {
+	path  string
+	owner []byte
+}
+
+// Path returns the lock file that guards target.
+func Path(target string) string {
+	return target + ".lock"
+}
+
+// Acquire takes the lock for target, waiting up to timeout for another
+// holder to release it and breaking it if it turns out to be stale.
+func Acquire(target string, timeout time.Duration) (*Lock, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		lock, err := TryAcquire(target)
+		if lock != nil || err != nil {
+			return lock, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w (%s)", ErrTimeout, Path(target))
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// TryAcquire takes the lock for target if it is free or stale and returns nil
+// without waiting if another process holds it.
+func TryAcquire(target string) (*Lock, error) {
+	path := Path(target)
+	owner := ownerRecord()
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, writeErr := file.Write(owner)
+			closeErr := file.Close()
+			if err := errors.Join(writeErr, closeErr); err != nil {
+				os.Remove(path)
+				return nil, err
+			}
+			return &Lock{path: path, owner: owner}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if !breakStale(path) {
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
+// Release removes the lock, unless it was broken as stale and another
+// process has since taken it.
+func (l *Lock) Release() error {
+	current, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current, l.owner) {
+		return nil
+	}
+	return os.Remove(l.path)
+}
+
+// ownerRecord identifies this process: "pid host unixnano".
+func ownerRecord() []byte {
+	host, _ := os.Hostname()
+	return fmt.Appendf(nil, "%d %s %d\n", os.Getpid(), host, time.Now().UnixNano())
+}
+
+// breakStale removes the lock at path if it is stale and reports whether it
+// did. The contents are re-read just before removal so a lock that was
+// replaced in the meantime is left alone.
+func breakStale(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		// Released between our create and this check: try again.
+		return errors.Is(err, os.ErrNotExist)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	if !isStale(contents, info.ModTime()) {
+		return false
+	}
+	if again, err := os.ReadFile(path); err != nil || !bytes.Equal(again, contents) {
+		return errors.Is(err, os.ErrNotExist)
+	}
+	return os.Remove(path) == nil
+}
+
+// isStale reports whether a lock is abandoned: older than StaleAfter, or
+// held by a process on this host that no longer exists.
+func isStale(contents []byte, modTime time.Time) bool {
+	if time.Since(modTime) > StaleAfter {
+		return true
+	}
+	fields := strings.Fields(string(contents))
+	if len(fields) < 2 {
+		// Half-written by a holder that is still creating it.
+		return false
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return false
+	}
+	host, _ := os.Hostname()
+	return fields[1] == host && !processAlive(pid)
+}
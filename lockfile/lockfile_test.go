@@ -0,0 +1,98 @@
+package lockfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestAcquireExcludesUntilRelease(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "tasks.json")
+	lock, err := Acquire(target, time.Second)
+	if err != nil || lock == nil {
+		t.Fatalf("Acquire = %v, %v", lock, err)
+	}
+
+	if other, err := TryAcquire(target); other != nil || err != nil {
+		t.Fatalf("expected a held lock to refuse, got %v, %v", other, err)
+	}
+	if _, err := Acquire(target, 50*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(Path(target)); !os.IsNotExist(err) {
+		t.Fatalf("expected the lock file removed, got %v", err)
+	}
+	next, err := TryAcquire(target)
+	if err != nil || next == nil {
+		t.Fatalf("expected the lock free after release, got %v, %v", next, err)
+	}
+	next.Release()
+}
+
+func TestStaleLocksAreBroken(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "tasks.json")
+	host, _ := os.Hostname()
+
+	// Abandoned long ago by a process on another machine.
+	if err := os.WriteFile(Path(target), []byte("1 elsewhere 0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * StaleAfter)
+	os.Chtimes(Path(target), old, old)
+	lock, err := TryAcquire(target)
+	if err != nil || lock == nil {
+		t.Fatalf("expected an old lock to be broken, got %v, %v", lock, err)
+	}
+	lock.Release()
+
+	// Fresh, but held by a process on another machine: respected.
+	if err := os.WriteFile(Path(target), []byte("1 elsewhere 0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if lock, _ := TryAcquire(target); lock != nil {
+		t.Fatal("a fresh lock from another host must be respected")
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	// Fresh, but its holder on this machine has exited.
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skip("cannot start a short-lived process:", err)
+	}
+	owner := fmt.Sprintf("%d %s 0\n", cmd.Process.Pid, host)
+	if err := os.WriteFile(Path(target), []byte(owner), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if lock, err := TryAcquire(target); err != nil || lock == nil {
+		t.Fatalf("expected a dead holder's lock to be broken, got %v, %v", lock, err)
+	}
+}
+
+func TestReleaseLeavesAReplacedLockAlone(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "tasks.json")
+	lock, err := Acquire(target, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another process judged our lock stale and took it over.
+	if err := os.WriteFile(Path(target), []byte("1 elsewhere 0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(Path(target)); err != nil {
+		t.Errorf("expected the other holder's lock kept, got %v", err)
+	}
+}
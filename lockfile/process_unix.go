@@ -0,0 +1,16 @@
+//go:build unix
+
+package lockfile
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive reports whether pid names a running process. Signal 0 checks
+// for existence without delivering anything; EPERM means it exists but
+// belongs to someone else.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
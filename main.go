@@ -6,9 +6,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
+	// Embedded zone data, so a configured time_zone works on systems
+	// without a zoneinfo database.
+	_ "time/tzdata"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/dtt101/doitdoit/cli"
 	"github.com/dtt101/doitdoit/config"
+	"github.com/dtt101/doitdoit/interchange"
 	"github.com/dtt101/doitdoit/model"
 	"github.com/dtt101/doitdoit/styles"
 )
@@ -16,11 +22,33 @@ import (
 func main() {
 	filePathFlag := flag.String("file", "", "Path to the JSON data file (overrides config)")
 	visibleDays := flag.Int("days", 3, "Number of days to display")
+	// --now is deliberately undocumented: it pretends the app was started at
+	// another moment, for reproducing midnight, DST, and weekday behaviour.
+	nowFlag := flag.String("now", "", "")
+	flag.Usage = usage
 	flag.Parse()
 
+	var clock model.Clock
+	if *nowFlag != "" {
+		// A wall-clock --now means the home zone's clock, so read that first.
+		home := time.Local
+		if cfg, err := config.LoadConfig(); err == nil {
+			home, _ = cfg.Location()
+		}
+		start, err := model.ParseNow(*nowFlag, home)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -now: %v\n", err)
+			os.Exit(2)
+		}
+		clock = model.StartingAt(start)
+	}
+
 	if args := flag.Args(); len(args) > 0 && args[0] == "config" {
 		os.Exit(config.RunCommand(args, os.Stdout))
 	}
+	if args := flag.Args(); len(args) > 0 && cli.IsCommand(args[0]) {
+		os.Exit(cli.Run(args, *filePathFlag, clock, os.Stdout, os.Stderr))
+	}
 	if *visibleDays < 1 {
 		fmt.Fprintln(os.Stderr, "Error: -days must be at least 1")
 		os.Exit(2)
@@ -55,6 +83,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	versions, backupDays := cfg.Backups()
+	save := model.SaveOptions{Backups: model.BackupPolicy{Versions: versions, Days: backupDays}}
+	if cfg.ICSFeed != "" {
+		feedPath, err := config.ExpandPath(cfg.ICSFeed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error expanding path: %v\n", err)
+			os.Exit(1)
+		}
+		save.AfterSave = interchange.ICSFeed(feedPath, interchange.ICSOptions{Events: cfg.ICSFeedEvents})
+	}
+	// LoadConfig has already checked the zone.
+	loc, _ := cfg.Location()
+	cal := model.Calendar{Clock: clock, Zone: loc, DayStart: cfg.DayStart()}
+
 	theme, err := styles.ResolveTheme(cfg.Theme)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not load theme %q (%v); using default\n", cfg.Theme, err)
@@ -69,11 +111,20 @@ func main() {
 		os.Exit(1)
 	}
 
-	m, err := model.NewModelWithRetention(finalPath, *visibleDays, retentionDays)
+	m, err := model.NewModelWithRetention(finalPath, *visibleDays, retentionDays, cal, save)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing model: %v\n", err)
 		os.Exit(1)
 	}
+	m.StaleAfter = cfg.Staleness()
+
+	// Bring the feed up to date with edits made elsewhere since it was
+	// last written.
+	if save.AfterSave != nil {
+		if err := save.AfterSave(m.Data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
 
 	p := tea.NewProgram(m)
 	watchThemeReload(p)
@@ -82,3 +133,16 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// usage prints the flags without the hidden ones.
+func usage() {
+	visible := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	visible.SetOutput(flag.CommandLine.Output())
+	flag.VisitAll(func(f *flag.Flag) {
+		if f.Name != "now" {
+			visible.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	fmt.Fprintf(visible.Output(), "Usage of %s:\n", os.Args[0])
+	visible.PrintDefaults()
+}
@@ -16,89 +16,73 @@ func insertAt(tasks []Task, idx int, task Task) []Task {
 }
 
 func (m *Model) addTask(title string) {
-	currentDate := m.getCurrentKey()
-	newTask := Task{
-		ID:        fmt.Sprintf("%d", time.Now().UnixNano()),
-		Title:     title,
-		CreatedAt: time.Now(),
-		Completed: false,
-	}
+	m.recordUndo(fmt.Sprintf("add %q", title))
+	m.Data.Add(m.getCurrentKey(), NewTask(title, m.Calendar.Now()))
+}
 
-	tasks := m.Data[currentDate]
-	insertIdx := len(tasks)
-	for i, t := range tasks {
-		if t.Completed {
-			insertIdx = i
-			break
-		}
+// editTask renames the selected task in place, keeping its ID, dates, and
+// position.
+func (m *Model) editTask(title string) bool {
+	task, ok := m.selectedTask()
+	if !ok || task.Title == title {
+		return false
 	}
 
-	if insertIdx == len(tasks) {
-		m.Data[currentDate] = append(tasks, newTask)
-	} else {
-		m.Data[currentDate] = insertAt(tasks, insertIdx, newTask)
-	}
+	m.recordUndo(fmt.Sprintf("edit %q", task.Title))
+	edited := &m.Data[m.getCurrentKey()][m.RowIdx]
+	edited.Title = title
+	edited.SyncLabels()
+	return true
 }
 
 func (m *Model) deleteTask() bool {
-	currentDate := m.getCurrentKey()
-	tasks := m.Data[currentDate]
-	if len(tasks) == 0 || m.RowIdx >= len(tasks) {
+	task, ok := m.selectedTask()
+	if !ok {
 		return false
 	}
-
-	m.Data[currentDate] = append(tasks[:m.RowIdx], tasks[m.RowIdx+1:]...)
+	m.recordUndo(fmt.Sprintf("delete %q", task.Title))
+	m.Data.Remove(m.getCurrentKey(), m.RowIdx)
 	m.clampRow()
 	return true
 }
 
 func (m *Model) toggleTask() bool {
-	currentDate := m.getCurrentKey()
-	tasks := m.Data[currentDate]
-	if m.RowIdx >= len(tasks) {
+	task, ok := m.selectedTask()
+	if !ok {
 		return false
 	}
+	verb := "complete"
+	if task.Completed {
+		verb = "reopen"
+	}
+	m.recordUndo(fmt.Sprintf("%s %q", verb, task.Title))
+	m.Data.Toggle(m.getCurrentKey(), m.RowIdx, m.Calendar)
+	// A recurring task's next occurrence may already be in view.
+	m.Data.distributeFutureTasksThrough(m.lastVisibleDate(), m.Calendar.Today())
+	return true
+}
 
-	// Toggle completion
-	tasks[m.RowIdx].Completed = !tasks[m.RowIdx].Completed
-
-	if tasks[m.RowIdx].Completed {
-		// If completed and not already at the bottom, move to bottom
-		if m.RowIdx < len(tasks)-1 {
-			task := tasks[m.RowIdx]
-			// Remove task at RowIdx
-			tasks = append(tasks[:m.RowIdx], tasks[m.RowIdx+1:]...)
-			// Append task to end
-			tasks = append(tasks, task)
-
-			// Update the map with the reordered slice
-			m.Data[currentDate] = tasks
-		}
-	} else {
-		// If uncompleted, move it above completed tasks
-		task := tasks[m.RowIdx]
-		// Remove task at current position
-		tasks = append(tasks[:m.RowIdx], tasks[m.RowIdx+1:]...)
-
-		// Find the first completed task to insert before it
-		insertIdx := len(tasks)
-		for i, t := range tasks {
-			if t.Completed {
-				insertIdx = i
-				break
-			}
-		}
-
-		// Insert at the appropriate position
-		if insertIdx == len(tasks) {
-			tasks = append(tasks, task)
-		} else {
-			tasks = insertAt(tasks, insertIdx, task)
-		}
+// setRepeat replaces the selected task's recurrence rule; an empty rule stops
+// it repeating.
+func (m *Model) setRepeat(rule string) bool {
+	task, ok := m.selectedTask()
+	if !ok || task.Repeat == rule {
+		return false
+	}
+	m.recordUndo(fmt.Sprintf("set repeat for %q", task.Title))
+	m.Data[m.getCurrentKey()][m.RowIdx].Repeat = rule
+	return true
+}
 
-		// Update the map with the reordered slice
-		m.Data[currentDate] = tasks
+// setDeadline sets or, with an empty date, clears the selected task's
+// deadline.
+func (m *Model) setDeadline(date string) bool {
+	task, ok := m.selectedTask()
+	if !ok || task.Deadline == date {
+		return false
 	}
+	m.recordUndo(fmt.Sprintf("set deadline for %q", task.Title))
+	m.Data[m.getCurrentKey()][m.RowIdx].Deadline = date
 	return true
 }
 
@@ -110,59 +94,35 @@ func cloneTodoData(data TodoData) TodoData {
 	return cloned
 }
 
-func (m *Model) captureMoveUndo() {
-	m.moveUndo = &moveUndoSnapshot{
-		Data:       cloneTodoData(m.Data),
-		ShowFuture: m.ShowFuture,
-		ColIdx:     m.ColIdx,
-		RowIdx:     m.RowIdx,
-	}
-}
-
-func (m *Model) clearMoveUndo() {
-	m.moveUndo = nil
-}
-
-func (m *Model) undoMove() bool {
-	if m.moveUndo == nil {
-		return false
-	}
-
-	snapshot := m.moveUndo
-	m.Data = cloneTodoData(snapshot.Data)
-	m.ShowFuture = snapshot.ShowFuture
-	m.ColIdx = snapshot.ColIdx
-	m.RowIdx = snapshot.RowIdx
-	m.moveUndo = nil
-	m.clampRow()
-	return true
-}
-
+// reorderTask swaps the selected task with its neighbour in the given
+// direction. With a filter active the neighbour is the next matching task,
+// so hidden tasks keep their places.
 func (m *Model) reorderTask(direction int) bool {
 	currentDate := m.getCurrentKey()
-	tasks := m.Data[currentDate]
-	if len(tasks) == 0 {
+	task, ok := m.selectedTask()
+	if !ok {
 		return false
 	}
-
-	newRowIdx := m.RowIdx + direction
-	if newRowIdx < 0 || newRowIdx >= len(tasks) {
+	newRowIdx, ok := m.nextVisibleRow(currentDate, m.RowIdx, direction)
+	if !ok {
 		return false
 	}
 
-	m.captureMoveUndo()
+	tasks := m.Data[currentDate]
+	m.recordUndo(fmt.Sprintf("reorder %q", task.Title))
 	tasks[m.RowIdx], tasks[newRowIdx] = tasks[newRowIdx], tasks[m.RowIdx]
 	m.RowIdx = newRowIdx
 	return true
 }
 
 func (m Model) moveBaseDate() time.Time {
+	today := m.Calendar.Today()
 	if !m.ShowFuture && m.ColIdx >= 0 && m.ColIdx < len(m.dateKeys) {
-		if parsed, err := parseDate(m.dateKeys[m.ColIdx]); err == nil {
+		if parsed, err := parseDate(m.dateKeys[m.ColIdx], today.Location()); err == nil {
 			return parsed
 		}
 	}
-	return startOfDay(time.Now())
+	return today
 }
 
 func (m Model) relativeMoveTarget(days int) moveTarget {
@@ -171,60 +131,28 @@ func (m Model) relativeMoveTarget(days int) moveTarget {
 
 func (m *Model) scheduleTask(target moveTarget) bool {
 	sourceKey := m.getCurrentKey()
-	tasks := m.Data[sourceKey]
-	if len(tasks) == 0 || m.RowIdx < 0 || m.RowIdx >= len(tasks) {
+	task, ok := m.selectedTask()
+	if !ok {
 		return false
 	}
 
-	task := tasks[m.RowIdx]
-	targetKey := "Future"
+	targetKey := FutureKey
 	dueDate := ""
 	if !target.Future {
-		parsed, err := parseDate(target.Date)
+		var err error
+		targetKey, dueDate, err = ScheduleKey(target.Date, m.Calendar.Today(), m.lastVisibleDate())
 		if err != nil {
 			return false
 		}
-		today := startOfDay(time.Now())
-		if parsed.Before(today) {
-			parsed = today
-		}
-		dueDate = parsed.Format(dateLayout)
 		target = moveTarget{Date: dueDate}
-
-		lastVisible := m.lastVisibleDate()
-		if !parsed.After(lastVisible) {
-			targetKey = dueDate
-		}
 	}
 
 	if sourceKey == targetKey && task.DueDate == dueDate {
 		return false
 	}
 
-	m.captureMoveUndo()
-	task.DueDate = dueDate
-
-	if sourceKey == targetKey {
-		tasks[m.RowIdx] = task
-		m.Data[sourceKey] = tasks
-	} else {
-		m.Data[sourceKey] = append(tasks[:m.RowIdx], tasks[m.RowIdx+1:]...)
-
-		targetTasks := m.Data[targetKey]
-		insertIdx := len(targetTasks)
-		for i, existing := range targetTasks {
-			if existing.Completed {
-				insertIdx = i
-				break
-			}
-		}
-		if insertIdx == len(targetTasks) {
-			m.Data[targetKey] = append(targetTasks, task)
-		} else {
-			m.Data[targetKey] = insertAt(targetTasks, insertIdx, task)
-		}
-	}
-
+	m.recordUndo(fmt.Sprintf("move %q", task.Title))
+	m.Data.relocate(sourceKey, m.RowIdx, targetKey, dueDate)
 	m.lastMoveTarget = &target
 	m.clampRow()
 	return true
@@ -238,20 +166,21 @@ func (m *Model) repeatMove() bool {
 }
 
 func (m *Model) copyTask() {
-	currentDate := m.getCurrentKey()
-	tasks := m.Data[currentDate]
-	if len(tasks) == 0 || m.RowIdx >= len(tasks) {
+	task, ok := m.selectedTask()
+	if !ok {
 		return
 	}
 
-	if err := clipboard.WriteAll(tasks[m.RowIdx].Title); err != nil {
+	if err := clipboard.WriteAll(task.Title); err != nil {
 		m.Err = err
 		return
 	}
 	m.copyFlash = true
 }
 
-func normalizeDueDateInput(dateStr string) (string, error) {
+// NormalizeDueDate accepts a date typed as YYYY-MM-DD or MM-DD (today's
+// year) and returns it as a YYYY-MM-DD key.
+func NormalizeDueDate(dateStr string, today time.Time) (string, error) {
 	dateStr = strings.TrimSpace(dateStr)
 	if dateStr == "" {
 		return "", fmt.Errorf("date is required in YYYY-MM-DD or MM-DD format")
@@ -260,7 +189,7 @@ func normalizeDueDateInput(dateStr string) (string, error) {
 	// If only M-D or MM-DD provided (no year), prepend current year
 	parts := strings.Split(dateStr, "-")
 	if len(parts) == 2 {
-		dateStr = fmt.Sprintf("%d-%s", time.Now().Year(), dateStr)
+		dateStr = fmt.Sprintf("%d-%s", today.Year(), dateStr)
 	}
 
 	parsed, err := time.Parse("2006-01-02", dateStr)
@@ -0,0 +1,273 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/lockfile"
+)
+
+// BackupsDir is the directory, beside the data file, that rolling backups are
+// kept in.
+const BackupsDir = "backups"
+
+// backupIDLayout names each backup after the moment it was taken, so IDs sort
+// in time order.
+const backupIDLayout = "20060102-150405"
+
+// ErrBackupNotFound means no backup has the requested ID.
+var ErrBackupNotFound = errors.New("no backup with that ID")
+
+// BackupPolicy is how many copies of the data file Save keeps before replacing
+// it: the Versions most recent, plus the first of each of the last Days days.
+// Zero Versions turns backups off.
+type BackupPolicy struct {
+	Versions int
+	Days     int
+}
+
+// Backup is one saved copy of the data file.
+type Backup struct {
+	ID   string
+	Path string
+	Time time.Time
+}
+
+// backupFile copies the data file at path into BackupsDir before Save replaces
+// it, then prunes copies the policy no longer keeps. A missing or empty file
+// has nothing worth keeping. Within one second only the first copy is kept,
+// since it holds the state from before that burst of saves. Backups are timed
+// by the wall clock, not the package Clock, as they record real writes.
+func backupFile(path string, policy BackupPolicy) error {
+	if policy.Versions <= 0 {
+		return nil
+	}
+	raw, err := readBackupSource(path)
+	if err != nil || raw == nil {
+		return err
+	}
+	now := time.Now()
+	target := backupPath(path, now.Format(backupIDLayout))
+	if _, err := os.Lstat(target); os.IsNotExist(err) {
+		if err := writeBackup(target, raw); err != nil {
+			return err
+		}
+	}
+	return pruneBackups(path, policy, now)
+}
+
+// keepCopy backs up the data file at path whatever the policy, before a
+// command the user did not think of as a save replaces it, and returns the
+// copy's ID. The copy gets an ID of its own, a second later than any taken
+// already, and is never pruned here. A missing or empty file has nothing
+// worth keeping and returns no ID.
+func keepCopy(path string) (string, error) {
+	raw, err := readBackupSource(path)
+	if err != nil || raw == nil {
+		return "", err
+	}
+	for taken := time.Now(); ; taken = taken.Add(time.Second) {
+		id := taken.Format(backupIDLayout)
+		target := backupPath(path, id)
+		if _, err := os.Lstat(target); os.IsNotExist(err) {
+			return id, writeBackup(target, raw)
+		}
+	}
+}
+
+// readBackupSource reads the data file at path for backing up. It returns nil
+// for a missing or empty file.
+func readBackupSource(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) || (err == nil && len(raw) == 0) {
+		return nil, nil
+	}
+	return raw, err
+}
+
+// writeBackup writes raw to target, creating BackupsDir if needed.
+func writeBackup(target string, raw []byte) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+	return WritePrivateFile(target, raw)
+}
+
+// pruneBackups removes the backups of path that policy does not keep as of
+// now.
+func pruneBackups(path string, policy BackupPolicy, now time.Time) error {
+	backups, err := Backups(path)
+	if err != nil {
+		return err
+	}
+	keep := make(map[string]bool)
+	for i, backup := range backups {
+		if i < policy.Versions {
+			keep[backup.ID] = true
+		}
+	}
+	// Backups are newest first, so the last one seen for a day is its first.
+	earliest := startOfDay(now).AddDate(0, 0, 1-policy.Days)
+	firstOfDay := make(map[string]string)
+	for _, backup := range backups {
+		if policy.Days > 0 && !backup.Time.Before(earliest) {
+			firstOfDay[backup.Time.Format(dateLayout)] = backup.ID
+		}
+	}
+	for _, id := range firstOfDay {
+		keep[id] = true
+	}
+
+	for _, backup := range backups {
+		if !keep[backup.ID] {
+			if err := os.Remove(backup.Path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// backupPath is where the backup with id of the data file at path lives.
+func backupPath(path, id string) string {
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(filepath.Base(path), ext)
+	return filepath.Join(filepath.Dir(path), BackupsDir, stem+"-"+id+ext)
+}
+
+// Backups lists the backups of the data file at path, newest first.
+func Backups(path string) ([]Backup, error) {
+	dir := filepath.Join(filepath.Dir(path), BackupsDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(filepath.Base(path), ext)
+
+	var backups []Backup
+	for _, entry := range entries {
+		id, ok := strings.CutPrefix(entry.Name(), stem+"-")
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		if id, ok = strings.CutSuffix(id, ext); !ok {
+			continue
+		}
+		taken, err := time.ParseInLocation(backupIDLayout, id, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{ID: id, Path: filepath.Join(dir, entry.Name()), Time: taken})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].ID > backups[j].ID })
+	return backups, nil
+}
+
+// FindBackup returns the backup of the data file at path with id.
+func FindBackup(path, id string) (Backup, error) {
+	backups, err := Backups(path)
+	if err != nil {
+		return Backup{}, err
+	}
+	for _, backup := range backups {
+		if backup.ID == id {
+			return backup, nil
+		}
+	}
+	return Backup{}, fmt.Errorf("%w: %s", ErrBackupNotFound, id)
+}
+
+// LoadBackup reads the tasks saved in a backup, migrating an older schema as
+// Load would.
+func LoadBackup(backup Backup) (TodoData, error) {
+	return loadRaw(backup.Path)
+}
+
+// RestoreBackup replaces the data file at path with the backup with id. The
+// file being replaced is backed up first, whatever the backup policy, and the
+// restore fails if that copy cannot be written. That copy is the save's only
+// backup; otherwise the restored file is saved as opts asks. It returns the
+// copy's ID, so a restore can be undone by restoring that copy; the ID is
+// empty if there was no file to replace.
+func RestoreBackup(path, id string, opts SaveOptions) (string, error) {
+	backup, err := FindBackup(path, id)
+	if err != nil {
+		return "", err
+	}
+	data, err := LoadBackup(backup)
+	if err != nil {
+		return "", fmt.Errorf("reading backup %s: %w", id, err)
+	}
+	unlock, err := lockDataFile(path, lockfile.DefaultTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	replaced, err := keepCopy(path)
+	if err != nil {
+		return "", fmt.Errorf("backing up %s: %w", filepath.Base(path), err)
+	}
+	opts.Backups = BackupPolicy{}
+	return replaced, data.Save(path, opts)
+}
+
+// BackupChange is one task that differs between a backup and the data file.
+type BackupChange struct {
+	// Kind is "added" for a task only in the data file, "removed" for one
+	// only in the backup, and "changed" for one that differs.
+	Kind  string
+	Key   string
+	Title string
+}
+
+// DiffBackup lists how the data file at path differs from the backup with id,
+// task by task: what has been added, removed, or changed since the backup was
+// taken. Changes are ordered by list, then title.
+func DiffBackup(path, id string) ([]BackupChange, error) {
+	backup, err := FindBackup(path, id)
+	if err != nil {
+		return nil, err
+	}
+	then, err := LoadBackup(backup)
+	if err != nil {
+		return nil, fmt.Errorf("reading backup %s: %w", id, err)
+	}
+	now, err := loadRaw(path)
+	if err != nil {
+		return nil, err
+	}
+
+	before, after := indexByID(then), indexByID(now)
+	var changes []BackupChange
+	for id, current := range after {
+		previous, ok := before[id]
+		switch {
+		case !ok:
+			changes = append(changes, BackupChange{Kind: "added", Key: current.Key, Title: current.Task.Title})
+		case previous.Key != current.Key || !reflect.DeepEqual(previous.Task, current.Task):
+			changes = append(changes, BackupChange{Kind: "changed", Key: current.Key, Title: current.Task.Title})
+		}
+	}
+	for id, previous := range before {
+		if _, ok := after[id]; !ok {
+			changes = append(changes, BackupChange{Kind: "removed", Key: previous.Key, Title: previous.Task.Title})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Key != changes[j].Key {
+			return changes[i].Key < changes[j].Key
+		}
+		return changes[i].Title < changes[j].Title
+	})
+	return changes, nil
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// keepTen is the default backup policy.
+var keepTen = SaveOptions{Backups: BackupPolicy{Versions: 10, Days: 7}}
+
+func TestSaveBacksUpReplacedFileOwnerOnly(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := (TodoData{today: {{ID: "a", Title: "First"}}}).Save(path, keepTen); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := Backups(path); len(backups) != 0 {
+		t.Fatalf("a new file has nothing to back up, got %v", backups)
+	}
+
+	if err := (TodoData{today: {{ID: "a", Title: "Second"}}}).Save(path, SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if backups, _ := Backups(path); len(backups) != 0 {
+		t.Fatalf("expected no backup with backups off, got %v", backups)
+	}
+	if err := (TodoData{today: {{ID: "a", Title: "Third"}}}).Save(path, keepTen); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := Backups(path)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("expected one backup, got %v, %v", backups, err)
+	}
+	info, err := os.Stat(backups[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("backup mode = %v, want 0600", info.Mode().Perm())
+	}
+	saved, err := LoadBackup(backups[0])
+	if err != nil || saved[today][0].Title != "Second" {
+		t.Errorf("expected the replaced version backed up, got %v, %v", saved, err)
+	}
+}
+
+func TestPruneBackupsKeepsRecentVersionsAndFirstOfEachDay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.MkdirAll(filepath.Join(filepath.Dir(path), BackupsDir), 0700); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 16, 18, 0, 0, 0, time.Local)
+	var ids []string
+	for _, taken := range []time.Time{
+		now.AddDate(0, 0, -9),                 // older than the daily window
+		now.AddDate(0, 0, -2).Add(-time.Hour), // first of its day
+		now.AddDate(0, 0, -2),                 // same day, later
+		now.Add(-3 * time.Hour),               // first of today
+		now.Add(-2 * time.Hour),
+		now.Add(-time.Hour),
+	} {
+		id := taken.Format(backupIDLayout)
+		ids = append(ids, id)
+		if err := os.WriteFile(backupPath(path, id), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := filepath.Join(filepath.Dir(path), BackupsDir, "notes-"+ids[0]+".json")
+	os.WriteFile(other, []byte("{}"), 0600)
+
+	if err := pruneBackups(path, BackupPolicy{Versions: 2, Days: 7}, now); err != nil {
+		t.Fatal(err)
+	}
+	backups, _ := Backups(path)
+	var kept []string
+	for _, backup := range backups {
+		kept = append(kept, backup.ID)
+	}
+	want := []string{ids[5], ids[4], ids[3], ids[1]}
+	if strings.Join(kept, ",") != strings.Join(want, ",") {
+		t.Errorf("kept %v, want %v", kept, want)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("another file's backups must be left alone, got %v", err)
+	}
+}
+
+func TestRestoreAndDiffBackup(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := (TodoData{today: {{ID: "a", Title: "Keep"}, {ID: "b", Title: "Lost"}}}).Save(path, keepTen); err != nil {
+		t.Fatal(err)
+	}
+	if err := (TodoData{today: {{ID: "a", Title: "Keep", Completed: true}, {ID: "c", Title: "New"}}}).Save(path, keepTen); err != nil {
+		t.Fatal(err)
+	}
+	backups, _ := Backups(path)
+	if len(backups) != 1 {
+		t.Fatalf("expected one backup, got %v", backups)
+	}
+	id := backups[0].ID
+
+	changes, err := DiffBackup(path, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.Kind+" "+change.Title)
+	}
+	if strings.Join(got, ",") != "changed Keep,removed Lost,added New" {
+		t.Errorf("diff = %v", got)
+	}
+
+	if _, err := DiffBackup(path, "19990101-000000"); err == nil {
+		t.Error("expected an unknown ID to fail")
+	}
+	// The backup was taken this second, so the replaced file needs an ID of
+	// its own.
+	replaced, err := RestoreBackup(path, id, SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := Load(path, 0, testCalendar().Today(), SaveOptions{})
+	if len(restored[today]) != 2 || restored[today][1].Title != "Lost" {
+		t.Errorf("expected the backup restored, got %v", restored)
+	}
+	if replaced == "" || replaced == id {
+		t.Fatalf("expected the replaced file kept under a new ID, got %q", replaced)
+	}
+	backup, err := FindBackup(path, replaced)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, err := LoadBackup(backup); err != nil || len(saved[today]) != 2 || saved[today][1].Title != "New" {
+		t.Errorf("expected the replaced version in %s, got %v, %v", replaced, saved, err)
+	}
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock tells the model what time it is. Every date decision — which day is
+// today, what rolls over, what surfaces from Future — goes through a
+// Calendar's clock rather than time.Now, so tests and the hidden --now flag
+// can move it.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock.
+type ClockFunc func() time.Time
+
+// Now calls f.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the real wall clock.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// Calendar is how dates are reckoned: the clock, the home time zone every
+// date key is read in, and when a new day begins. The zero Calendar reads
+// the system clock in the system zone, with days starting at midnight.
+type Calendar struct {
+	// Clock is nil for SystemClock.
+	Clock Clock
+	// Zone is the home time zone, so devices in different zones sharing
+	// one file agree on the day; nil follows the system zone.
+	Zone *time.Location
+	// DayStart is how long after midnight a new day begins, so a night owl
+	// working at 00:30 is still on the day before: tasks roll over, "today"
+	// moves, and Future tasks surface only once that hour has passed.
+	DayStart time.Duration
+}
+
+// Location returns the home time zone.
+func (c Calendar) Location() *time.Location {
+	if c.Zone == nil {
+		return time.Local
+	}
+	return c.Zone
+}
+
+// Now returns the current time according to the clock, in the home time
+// zone and without a monotonic reading, so a time stamped on a task
+// compares equal once saved.
+func (c Calendar) Now() time.Time {
+	clock := c.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	return clock.Now().In(c.Location()).Round(0)
+}
+
+// Today returns midnight, in the home time zone, of the current day as the
+// clock and the day start make it.
+func (c Calendar) Today() time.Time {
+	return c.DayOf(c.Now())
+}
+
+// DayOf returns midnight, in the home time zone, of the day t falls in,
+// counting times before the day start as the previous day.
+func (c Calendar) DayOf(t time.Time) time.Time {
+	return startOfDay(t.In(c.Location()).Add(-c.DayStart))
+}
+
+// FixedClock returns a clock stopped at t.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// StartingAt returns a clock that reads start now and then runs forward in
+// step with the wall clock, so a session started just before midnight crosses
+// it for real.
+func StartingAt(start time.Time) Clock {
+	offset := time.Until(start)
+	return ClockFunc(func() time.Time { return time.Now().Add(offset) })
+}
+
+// nowLayouts are the forms ParseNow accepts, most specific first.
+var nowLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", dateLayout}
+
+// ParseNow reads a --now value: an RFC 3339 timestamp, a
+// "YYYY-MM-DDTHH:MM[:SS]" in the home time zone loc, or a bare YYYY-MM-DD
+// for that day's midnight there.
+func ParseNow(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range nowLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q; use YYYY-MM-DD, YYYY-MM-DDTHH:MM, or RFC 3339", value)
+}
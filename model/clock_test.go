@@ -0,0 +1,182 @@
+package model
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// calendarAt returns a calendar in the system zone whose clock is stopped at
+// now.
+func calendarAt(now time.Time) Calendar {
+	return Calendar{Clock: FixedClock(now)}
+}
+
+// testNow is when the model tests pretend to run, 09:30 on Friday 2026-10-16
+// in the current time.Local, so their dates never depend on the day the
+// suite runs.
+func testNow() time.Time {
+	return time.Date(2026, 10, 16, 9, 30, 0, 0, time.Local)
+}
+
+// testCalendar is a calendar stopped at testNow.
+func testCalendar() Calendar {
+	return calendarAt(testNow())
+}
+
+func TestParseNow(t *testing.T) {
+	withTimeZone(t, "EST", -5)
+	cases := map[string]time.Time{
+		"2026-03-08":                time.Date(2026, 3, 8, 0, 0, 0, 0, time.Local),
+		"2026-03-08T01:59":          time.Date(2026, 3, 8, 1, 59, 0, 0, time.Local),
+		"2026-03-08 01:59":          time.Date(2026, 3, 8, 1, 59, 0, 0, time.Local),
+		"2026-03-08T01:59:30":       time.Date(2026, 3, 8, 1, 59, 30, 0, time.Local),
+		"2026-03-08T06:59:30Z":      time.Date(2026, 3, 8, 6, 59, 30, 0, time.UTC),
+		"2026-03-08T01:59:30-05:00": time.Date(2026, 3, 8, 6, 59, 30, 0, time.UTC),
+	}
+	for value, want := range cases {
+		got, err := ParseNow(value, time.Local)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseNow(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	if _, err := ParseNow("next tuesday", time.Local); err == nil {
+		t.Error("expected an unparseable time to fail")
+	}
+}
+
+func TestStartingAtRunsForward(t *testing.T) {
+	start := time.Date(2026, 3, 7, 23, 59, 59, 0, time.UTC)
+	clock := StartingAt(start)
+	first := clock.Now()
+	if first.Before(start) || first.Sub(start) > time.Second {
+		t.Fatalf("clock started at %v, want %v", first, start)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if !clock.Now().After(first) {
+		t.Error("expected the clock to keep running")
+	}
+}
+
+func TestModelFollowsClockAcrossMidnight(t *testing.T) {
+	zone, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tz database:", err)
+	}
+	orig := time.Local
+	time.Local = zone
+	t.Cleanup(func() { time.Local = orig })
+
+	// Clocks go forward at 02:00 on 2026-03-08, so the day is 23 hours long.
+	now := time.Date(2026, 3, 7, 23, 59, 0, 0, zone)
+	cal := Calendar{Clock: ClockFunc(func() time.Time { return now })}
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := (TodoData{
+		"2026-03-07": {{ID: "open", Title: "Carry me over"}},
+		FutureKey:    {{ID: "monday", Title: "Monday thing", DueDate: "2026-03-09"}},
+		"2026-03-06": {{ID: "done", Title: "Finished", Completed: true}},
+	}).Save(path, SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewModelWithRetention(path, 2, 0, cal, SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.todayKey != "2026-03-07" || m.dateKeys[0] != "2026-03-07" || len(m.Data[FutureKey]) != 1 {
+		t.Fatalf("before midnight: today %s, keys %v, future %v", m.todayKey, m.dateKeys, m.Data[FutureKey])
+	}
+
+	now = time.Date(2026, 3, 8, 0, 1, 0, 0, zone)
+	next, _ := m.Update(dateTickMsg(now))
+	m = next.(Model)
+	if m.todayKey != "2026-03-08" || m.dateKeys[0] != "2026-03-08" || m.dateKeys[1] != "2026-03-09" {
+		t.Fatalf("after midnight: today %s, keys %v", m.todayKey, m.dateKeys)
+	}
+	if got := m.Data["2026-03-08"]; len(got) != 1 || got[0].ID != "open" {
+		t.Errorf("expected the open task rolled over, got %v", got)
+	}
+	if got := m.Data["2026-03-09"]; len(got) != 1 || got[0].ID != "monday" {
+		t.Errorf("expected Monday's task to surface, got %v", m.Data)
+	}
+
+	// The next day starts 23 hours later, not 24.
+	now = time.Date(2026, 3, 8, 23, 30, 0, 0, zone)
+	next, _ = m.Update(dateTickMsg(now))
+	if m = next.(Model); m.todayKey != "2026-03-08" {
+		t.Errorf("still Sunday at 23:30, got %s", m.todayKey)
+	}
+	now = time.Date(2026, 3, 9, 0, 0, 0, 0, zone)
+	next, _ = m.Update(dateTickMsg(now))
+	if m = next.(Model); m.todayKey != "2026-03-09" {
+		t.Errorf("expected Monday at midnight, got %s", m.todayKey)
+	}
+}
+
+func TestAddedTaskUsesClockButUniqueIDs(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	m := Model{
+		Data:        TodoData{},
+		Calendar:    calendarAt(at),
+		VisibleDays: 1,
+		dateKeys:    []string{"2026-10-16"},
+	}
+	m.addTask("a")
+	m.addTask("b")
+	first, second := m.Data["2026-10-16"][0], m.Data["2026-10-16"][1]
+	if !first.CreatedAt.Equal(at) {
+		t.Errorf("CreatedAt = %v, want the clock's %v", first.CreatedAt, at)
+	}
+	if first.ID == second.ID {
+		t.Error("a stopped clock must not make IDs collide")
+	}
+}
+
+func TestDayStartDelaysTheNewDay(t *testing.T) {
+	cal := calendarAt(time.Date(2026, 10, 17, 0, 30, 0, 0, time.Local))
+	cal.DayStart = 4 * time.Hour
+
+	today := cal.Today()
+	if got := today.Format(dateLayout); got != "2026-10-16" {
+		t.Fatalf("at 00:30 with a 04:00 day start, today = %s, want the day before", got)
+	}
+	data := TodoData{
+		"2026-10-16": {{ID: "late", Title: "Still working on it"}},
+		FutureKey:    {{ID: "sat", Title: "Saturday", DueDate: "2026-10-17"}},
+	}
+	if data.rollOverIncompleteTasks(today) {
+		t.Errorf("nothing should roll over before the day starts, got %v", data)
+	}
+	data.DistributeFutureTasks(1, today)
+	if len(data[FutureKey]) != 1 {
+		t.Errorf("Saturday's task surfaced before Saturday began: %v", data)
+	}
+	if key, _, _ := ScheduleKey("2026-10-16", today, today); key != "2026-10-16" {
+		t.Errorf("scheduling for the current day gave %s", key)
+	}
+
+	cal.Clock = FixedClock(time.Date(2026, 10, 17, 4, 0, 0, 0, time.Local))
+	if !data.rollOverIncompleteTasks(cal.Today()) || len(data["2026-10-17"]) != 1 {
+		t.Errorf("expected the task rolled over once the day started, got %v", data)
+	}
+}
+
+func TestHomeLocationDecidesToday(t *testing.T) {
+	// The laptop is in New York, but the file's home is London, where it is
+	// already the next morning.
+	withTimeZone(t, "EST", -5)
+	london := time.FixedZone("BST", 3600)
+	cal := calendarAt(time.Date(2026, 10, 16, 23, 30, 0, 0, time.Local))
+	cal.Zone = london
+
+	today := cal.Today()
+	if today.Format(dateLayout) != "2026-10-17" || today.Location() != london {
+		t.Fatalf("today = %v, want midnight on the 17th in London", today)
+	}
+	if day, _ := parseDate("2026-10-17", today.Location()); !day.Equal(today) {
+		t.Errorf("date keys should be read in the home zone, got %v", day)
+	}
+	data := TodoData{"2026-10-16": {{ID: "1", Title: "Yesterday in London"}}}
+	if !data.rollOverIncompleteTasks(today) || len(data["2026-10-17"]) != 1 {
+		t.Errorf("expected the task rolled over by London's date, got %v", data)
+	}
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/dtt101/doitdoit/styles"
+)
+
+// addConflicts queues merge conflicts for review. A newer conflict on the
+// same task replaces the one already queued.
+func (m *Model) addConflicts(conflicts []mergeConflict) {
+	for _, conflict := range conflicts {
+		m.conflicts = slices.DeleteFunc(m.conflicts, func(queued mergeConflict) bool {
+			return queued.ID == conflict.ID
+		})
+		m.conflicts = append(m.conflicts, conflict)
+	}
+}
+
+func conflictSummary(count int) string {
+	if count == 1 {
+		return "1 task changed here and elsewhere: press C to review"
+	}
+	return fmt.Sprintf("%d tasks changed here and elsewhere: press C to review", count)
+}
+
+func (m Model) handleResolvingConflictsKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if len(m.conflicts) == 0 {
+		m.State = Browsing
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc", "q":
+		m.State = Browsing
+	case "enter", "k":
+		m.conflicts = m.conflicts[1:]
+	case "o":
+		m.useOtherVersion(m.conflicts[0])
+		m.conflicts = m.conflicts[1:]
+		m.persist()
+	}
+	if len(m.conflicts) == 0 && m.State == ResolvingConflicts {
+		m.State = Browsing
+		m.status = "All conflicts resolved"
+	}
+	return m, nil
+}
+
+// useOtherVersion replaces the kept version of a conflicting task with the
+// one the merge set aside, deleting the task if that version had.
+func (m *Model) useOtherVersion(conflict mergeConflict) {
+	m.recordUndo(fmt.Sprintf("resolve conflict for %q", conflict.Title()))
+	if key, idx, ok := m.Data.locate(conflict.ID); ok {
+		m.Data.Remove(key, idx)
+	}
+	if conflict.Other != nil {
+		m.Data.Add(conflict.Other.Key, conflict.Other.Task)
+	}
+	m.clampRow()
+}
+
+// describeConflictSide summarises one version of a conflicting task.
+func describeConflictSide(side *placedTask, today time.Time, field func(name, value string) string) []string {
+	if side == nil {
+		return []string{field("Status", "Deleted")}
+	}
+	task := side.Task
+	status := "Open"
+	if task.Completed {
+		status = "Completed"
+	}
+	list := describeKey(side.Key, today)
+	if side.Key == FutureKey && task.DueDate != "" {
+		list += " · " + task.DueDate
+	}
+	lines := []string{field("Title", task.Title), field("Status", status), field("List", list)}
+	if task.Repeat != "" {
+		lines = append(lines, field("Repeats", task.Repeat))
+	}
+	if task.Notes != "" {
+		notes, _, _ := strings.Cut(task.Notes, "\n")
+		lines = append(lines, field("Notes", notes))
+	}
+	return lines
+}
+
+func (m Model) conflictsModalView() string {
+	frame := m.modal(64, styles.Warning)
+	innerWidth := frame.innerWidth()
+
+	if len(m.conflicts) == 0 {
+		return frame.renderModal("Sync conflicts", "No conflicts to review.")
+	}
+	conflict := m.conflicts[0]
+	today := m.Calendar.Today()
+
+	label := lipgloss.NewStyle().Foreground(styles.Subtle)
+	field := func(name, value string) string {
+		return label.Render(fmt.Sprintf("%-10s", name)) +
+			lipgloss.NewStyle().Foreground(styles.Text).MaxWidth(max(1, innerWidth-10)).Render(value)
+	}
+
+	lines := []string{
+		label.Width(innerWidth).Render("This task was changed here and elsewhere before the two were merged."),
+		"",
+		label.Render("Kept"),
+	}
+	lines = append(lines, describeConflictSide(conflict.Kept, today, field)...)
+	lines = append(lines, "", label.Render("Other"))
+	lines = append(lines, describeConflictSide(conflict.Other, today, field)...)
+	lines = append(lines, "", label.Render("enter keep · o use other · esc later"))
+	title := fmt.Sprintf("Conflict %d of %d", 1, len(m.conflicts))
+	return frame.renderModal(title, lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/lockfile"
+)
+
+// CopiesArchiveDir is the directory, beside the data file, that reconciled
+// conflicted copies are moved into.
+const CopiesArchiveDir = "conflicted-copies"
+
+// ConflictedCopies lists the siblings that sync providers leave next to the
+// data file at path when two devices write at once: Dropbox's
+// "name (… conflicted copy …).json" and Syncthing's
+// "name.sync-conflict-….json". Paths are returned sorted.
+func ConflictedCopies(path string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(filepath.Base(path), ext)
+
+	var copies []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && isConflictedCopy(entry.Name(), stem, ext) {
+			copies = append(copies, filepath.Join(filepath.Dir(path), entry.Name()))
+		}
+	}
+	sort.Strings(copies)
+	return copies, nil
+}
+
+func isConflictedCopy(name, stem, ext string) bool {
+	middle, ok := strings.CutSuffix(name, ext)
+	if !ok {
+		return false
+	}
+	if rest, ok := strings.CutPrefix(middle, stem+" ("); ok {
+		return strings.HasSuffix(rest, ")") && strings.Contains(strings.ToLower(rest), "conflicted copy")
+	}
+	rest, ok := strings.CutPrefix(middle, stem+".sync-conflict-")
+	return ok && rest != ""
+}
+
+// Reconciliation reports what ReconcileConflictedCopies did.
+type Reconciliation struct {
+	// Copies are the conflicted copies that were merged and archived.
+	Copies []string
+	// Added counts tasks found only in a copy.
+	Added int
+	// Differed names tasks changed differently in a copy and the data file.
+	// The data file's value of each disputed field was kept; the copy's
+	// survives in the archive.
+	Differed []string
+	// ArchiveDir is where the copies now live.
+	ArchiveDir string
+}
+
+// ReconcileConflictedCopies merges every conflicted copy of the data file at
+// path into it by task ID, so a task that exists in any copy survives, then
+// moves the copies into CopiesArchiveDir. The data file is saved before any
+// copy is moved, and an unreadable copy stops the run before either. The
+// data is loaded as by Load.
+func ReconcileConflictedCopies(path string, retentionDays int, today time.Time, opts SaveOptions) (Reconciliation, error) {
+	unlock, err := lockDataFile(path, lockfile.DefaultTimeout)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+	defer unlock()
+
+	copies, err := ConflictedCopies(path)
+	if err != nil || len(copies) == 0 {
+		return Reconciliation{}, err
+	}
+	data, err := load(path, retentionDays, today, opts)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+
+	result := Reconciliation{
+		Copies:     copies,
+		ArchiveDir: filepath.Join(filepath.Dir(path), CopiesArchiveDir),
+	}
+	for _, copyPath := range copies {
+		copied, err := loadRaw(copyPath)
+		if err != nil {
+			return Reconciliation{}, fmt.Errorf("reading %s: %w", filepath.Base(copyPath), err)
+		}
+		known := indexByID(data)
+		for id := range indexByID(copied) {
+			if _, ok := known[id]; !ok {
+				result.Added++
+			}
+		}
+		// With no common ancestor, a field set on only one side is taken
+		// and any other difference is a conflict the data file wins.
+		merged, conflicts := mergeData(nil, data, copied)
+		for _, conflict := range conflicts {
+			result.Differed = append(result.Differed, conflict.Title())
+		}
+		data = merged
+	}
+	data.rollOverIncompleteTasks(today)
+	if err := data.Save(path, opts); err != nil {
+		return Reconciliation{}, err
+	}
+
+	if err := os.MkdirAll(result.ArchiveDir, 0700); err != nil {
+		return Reconciliation{}, err
+	}
+	for _, copyPath := range copies {
+		if err := os.Rename(copyPath, archivePath(result.ArchiveDir, filepath.Base(copyPath))); err != nil {
+			return Reconciliation{}, err
+		}
+	}
+	return result, nil
+}
+
+// archivePath picks a free name for name in dir, numbering repeats.
+func archivePath(dir, name string) string {
+	target := filepath.Join(dir, name)
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(target); os.IsNotExist(err) {
+			return target
+		}
+		target = filepath.Join(dir, fmt.Sprintf("%s.%d", name, i))
+	}
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestConflictedCopiesMatchesSyncProviderNames(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doitdoit.json")
+	for _, name := range []string{
+		"doitdoit.json",
+		"doitdoit (conflicted copy 2026-10-01).json",
+		"doitdoit (Sam's conflicted copy 2026-10-02).json",
+		"doitdoit.sync-conflict-20261001-120000-ABCDEFG.json",
+		"doitdoit (copy).json",
+		"other (conflicted copy 2026-10-01).json",
+		"doitdoit.sync-conflict-.json",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "doitdoit (conflicted copy dir).json"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	copies, err := ConflictedCopies(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, copyPath := range copies {
+		names = append(names, filepath.Base(copyPath))
+	}
+	want := "doitdoit (Sam's conflicted copy 2026-10-02).json|doitdoit (conflicted copy 2026-10-01).json|doitdoit.sync-conflict-20261001-120000-ABCDEFG.json"
+	if got := strings.Join(names, "|"); got != want {
+		t.Errorf("copies = %s, want %s", got, want)
+	}
+}
+
+func TestReconcileConflictedCopiesKeepsEveryTask(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "doitdoit.json")
+	if err := (TodoData{today: {{ID: "a", Title: "Shared"}, {ID: "b", Title: "Only here"}}}).Save(path, SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	copyPath := filepath.Join(dir, "doitdoit (conflicted copy 2026-10-01).json")
+	if err := (TodoData{today: {{ID: "a", Title: "Shared, renamed"}, {ID: "c", Title: "Only in copy"}}}).Save(copyPath, SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ReconcileConflictedCopies(path, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Added != 1 || len(result.Differed) != 1 || result.Differed[0] != "Shared" {
+		t.Errorf("unexpected report %+v", result)
+	}
+
+	data, err := Load(path, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(taskIDs(data[today]), ","); got != "a,c,b" {
+		t.Errorf("today = %s, want a,c,b", got)
+	}
+	if _, err := os.Stat(copyPath); !os.IsNotExist(err) {
+		t.Errorf("expected the copy moved away, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, CopiesArchiveDir, filepath.Base(copyPath))); err != nil {
+		t.Errorf("expected the copy archived: %v", err)
+	}
+	if copies, _ := ConflictedCopies(path); len(copies) != 0 {
+		t.Errorf("archived copies must not be found again, got %v", copies)
+	}
+}
+
+func TestReloadWarnsAboutConflictedCopies(t *testing.T) {
+	m := newReloadTestModel(t)
+	m.width, m.height = 140, 30
+	copyPath := strings.TrimSuffix(m.FilePath, ".json") + ".sync-conflict-20261001-120000-XYZ.json"
+	if err := os.WriteFile(copyPath, []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := m.Update(checkDataFile(m.FilePath, m.dataModTime, m.dataSize)())
+	m = updated.(Model)
+	if footer := ansi.Strip(m.helpView()); !strings.Contains(footer, "1 conflicted copy") || !strings.Contains(footer, "doitdoit doctor conflicts") {
+		t.Errorf("expected a conflicted-copy warning in the footer, got %q", footer)
+	}
+}
@@ -2,7 +2,6 @@ package model
 
 import (
 	"testing"
-	"time"
 
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
@@ -10,6 +9,7 @@ import (
 
 func TestSetTaskDateRejectsInvalidDate(t *testing.T) {
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{"Future": {{ID: "1", Title: "Task"}}},
 		VisibleDays: 3,
 		ShowFuture:  true,
@@ -35,8 +35,9 @@ func TestSetTaskDateRejectsInvalidDate(t *testing.T) {
 }
 
 func TestSetTaskDateNormalizesValidDate(t *testing.T) {
-	tomorrow := time.Now().AddDate(0, 0, 1).Format(dateLayout)
+	tomorrow := testNow().AddDate(0, 0, 1).Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{"Future": {{ID: "1", Title: "Task"}}},
 		VisibleDays: 3,
 		ShowFuture:  true,
@@ -70,9 +71,10 @@ func TestSetTaskDateNormalizesValidDate(t *testing.T) {
 }
 
 func TestMoveDateNormalizesPastDateToToday(t *testing.T) {
-	today := time.Now().Format(dateLayout)
-	yesterday := time.Now().AddDate(0, 0, -1).Format(dateLayout)
+	today := testNow().Format(dateLayout)
+	yesterday := testNow().AddDate(0, 0, -1).Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{"Future": {{ID: "1", Title: "Task"}}},
 		VisibleDays: 3,
 		ShowFuture:  true,
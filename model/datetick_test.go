@@ -3,14 +3,14 @@ package model
 import (
 	"path/filepath"
 	"testing"
-	"time"
 )
 
 func TestDateTickRollsOverAtMidnight(t *testing.T) {
-	today := time.Now().Format("2006-01-02")
-	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	today := testNow().Format("2006-01-02")
+	yesterday := testNow().AddDate(0, 0, -1).Format("2006-01-02")
 
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        make(TodoData),
 		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
 		VisibleDays: 3,
@@ -23,7 +23,7 @@ func TestDateTickRollsOverAtMidnight(t *testing.T) {
 		{ID: "2", Title: "Done already", Completed: true},
 	}
 
-	newM, cmd := m.Update(dateTickMsg(time.Now()))
+	newM, cmd := m.Update(dateTickMsg(testNow()))
 	m = newM.(Model)
 
 	if cmd == nil {
@@ -47,8 +47,9 @@ func TestDateTickRollsOverAtMidnight(t *testing.T) {
 }
 
 func TestDateTickNoChangeSameDay(t *testing.T) {
-	today := time.Now().Format("2006-01-02")
+	today := testNow().Format("2006-01-02")
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Task"}}},
 		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
 		VisibleDays: 3,
@@ -56,7 +57,7 @@ func TestDateTickNoChangeSameDay(t *testing.T) {
 	}
 	m.updateDateKeys()
 
-	newM, cmd := m.Update(dateTickMsg(time.Now()))
+	newM, cmd := m.Update(dateTickMsg(testNow()))
 	m = newM.(Model)
 
 	if cmd == nil {
@@ -72,11 +73,12 @@ func TestDateTickNoChangeSameDay(t *testing.T) {
 }
 
 func TestDateTickPreservesFocusedDateAfterRollover(t *testing.T) {
-	today := time.Now().Format("2006-01-02")
-	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
-	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	today := testNow().Format("2006-01-02")
+	yesterday := testNow().AddDate(0, 0, -1).Format("2006-01-02")
+	tomorrow := testNow().AddDate(0, 0, 1).Format("2006-01-02")
 
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Keep focus here"}}},
 		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
 		VisibleDays: 3,
@@ -85,7 +87,7 @@ func TestDateTickPreservesFocusedDateAfterRollover(t *testing.T) {
 		dateKeys:    []string{yesterday, today, tomorrow},
 	}
 
-	newM, _ := m.Update(dateTickMsg(time.Now()))
+	newM, _ := m.Update(dateTickMsg(testNow()))
 	m = newM.(Model)
 
 	if m.dateKeys[m.ColIdx] != today {
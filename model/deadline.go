@@ -0,0 +1,45 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// A deadline is the day a task must be finished by. DueDate says when a task
+// is planned and follows it through rollovers and moves; Deadline is only
+// ever changed by whoever set it, so "planned for today" and "must ship by
+// Friday" stay distinct.
+
+// DeadlineIn returns how many days remain until the task's deadline as of
+// today, negative once it has passed; the deadline is read in today's
+// location. It reports false when the task has no valid deadline.
+func (t Task) DeadlineIn(today time.Time) (int, bool) {
+	if t.Deadline == "" {
+		return 0, false
+	}
+	deadline, err := parseDate(t.Deadline, today.Location())
+	if err != nil {
+		return 0, false
+	}
+	return daysBetween(today, deadline), true
+}
+
+// DeadlineBadge describes an open task's deadline relative to today — "due
+// today", "due tomorrow", "due in 3d", or "overdue 2d" — and reports whether
+// it has passed. Completed tasks and tasks without a deadline get no badge.
+func (t Task) DeadlineBadge(today time.Time) (string, bool) {
+	days, ok := t.DeadlineIn(today)
+	if !ok || t.Completed {
+		return "", false
+	}
+	switch {
+	case days < 0:
+		return fmt.Sprintf("overdue %dd", -days), true
+	case days == 0:
+		return "due today", false
+	case days == 1:
+		return "due tomorrow", false
+	default:
+		return fmt.Sprintf("due in %dd", days), false
+	}
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestDeadlineBadge(t *testing.T) {
+	withTimeZone(t, "EST", -5)
+	today := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	cases := map[string]struct {
+		badge   string
+		overdue bool
+	}{
+		"2026-10-11": {"overdue 3d", true},
+		"2026-10-14": {"due today", false},
+		"2026-10-15": {"due tomorrow", false},
+		"2026-10-16": {"due in 2d", false},
+		"":           {"", false},
+		"someday":    {"", false},
+	}
+	for deadline, want := range cases {
+		badge, overdue := Task{Deadline: deadline}.DeadlineBadge(today)
+		if badge != want.badge || overdue != want.overdue {
+			t.Errorf("deadline %q: got (%q, %v), want (%q, %v)", deadline, badge, overdue, want.badge, want.overdue)
+		}
+	}
+
+	if badge, _ := (Task{Deadline: "2026-10-11", Completed: true}).DeadlineBadge(today); badge != "" {
+		t.Errorf("completed tasks should have no badge, got %q", badge)
+	}
+}
+
+func TestDeadlineCountsDaysAcrossDST(t *testing.T) {
+	zone, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tz database:", err)
+	}
+	// The clocks go back on 2026-11-01, so that day is 25 hours long.
+	today := time.Date(2026, 10, 31, 0, 0, 0, 0, zone)
+	if days, _ := (Task{Deadline: "2026-11-02"}).DeadlineIn(today); days != 2 {
+		t.Errorf("days = %d, want 2", days)
+	}
+}
+
+func TestRolloverLeavesDeadlineAlone(t *testing.T) {
+	today := calendarAt(time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local)).Today()
+	data := TodoData{"2026-10-13": {{ID: "1", Title: "File taxes", Deadline: "2026-10-13"}}}
+
+	data.rollOverIncompleteTasks(today)
+	rolled := data["2026-10-14"]
+	if len(rolled) != 1 || rolled[0].Deadline != "2026-10-13" {
+		t.Fatalf("expected the task rolled to today with its deadline kept, got %v", data)
+	}
+	if badge, overdue := rolled[0].DeadlineBadge(today); !overdue || badge != "overdue 1d" {
+		t.Errorf("badge = %q (overdue %v), want overdue 1d", badge, overdue)
+	}
+}
+
+func TestTUISetsDeadline(t *testing.T) {
+	// A Wednesday, so Friday is two days away.
+	today, friday := "2026-10-14", "2026-10-16"
+	m := Model{
+		Data:        TodoData{today: {{ID: "1", Title: "Quarterly report"}}},
+		Calendar:    calendarAt(time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local)),
+		VisibleDays: 1,
+		State:       Browsing,
+		TextInput:   textinput.New(),
+		dateKeys:    []string{today},
+	}
+
+	m = pressRune(m, 'D')
+	if m.State != SettingDeadline {
+		t.Fatalf("expected deadline input, got %v", m.State)
+	}
+	m.TextInput.SetValue("soon")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m = updated.(Model); m.State != SettingDeadline || m.Err == nil {
+		t.Fatalf("expected an invalid date to keep the input open with an error, got %v", m.State)
+	}
+	m.TextInput.SetValue(friday)
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if m.State != Browsing || m.Data[today][0].Deadline != friday {
+		t.Fatalf("expected deadline saved, got %+v", m.Data[today][0])
+	}
+	if view := m.renderDaySection(today, 0, 40); !strings.Contains(view, "⚑ due in 2d") {
+		t.Errorf("expected a deadline badge in the day section, got:\n%s", view)
+	}
+	if m.Data[today][0].DueDate != "" {
+		t.Errorf("setting a deadline should not schedule the task, got due %q", m.Data[today][0].DueDate)
+	}
+
+	m = pressRune(m, 'D')
+	if m.TextInput.Value() != friday {
+		t.Errorf("expected the input prefilled with the deadline, got %q", m.TextInput.Value())
+	}
+	m.TextInput.SetValue("none")
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if m.Data[today][0].Deadline != "" {
+		t.Fatalf("expected none to clear the deadline, got %q", m.Data[today][0].Deadline)
+	}
+
+	m = pressRune(m, 'u')
+	if m.Data[today][0].Deadline != friday {
+		t.Errorf("expected undo to restore the deadline, got %q", m.Data[today][0].Deadline)
+	}
+}
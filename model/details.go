@@ -0,0 +1,224 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/dtt101/doitdoit/styles"
+)
+
+// notesEditedMsg returns from the external editor with the task it was opened
+// for, so the notes land on the right task even if the cursor moved.
+type notesEditedMsg struct {
+	id    string
+	notes string
+	err   error
+}
+
+// locate finds a task by ID anywhere in the data.
+func (d TodoData) locate(id string) (key string, idx int, ok bool) {
+	for key, tasks := range d {
+		for i, task := range tasks {
+			if task.ID == id {
+				return key, i, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+func (m *Model) openDetails() bool {
+	task, ok := m.selectedTask()
+	if !ok {
+		return false
+	}
+	m.detailID = task.ID
+	m.State = ViewingDetails
+	return true
+}
+
+func (m Model) handleViewingDetailsKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "i", "q":
+		m.State = Browsing
+		m.detailID = ""
+	case "n":
+		if m.history {
+			m.status = historyReadOnly
+			return m, nil
+		}
+		key, idx, ok := m.Data.locate(m.detailID)
+		if !ok {
+			m.State = Browsing
+			return m, nil
+		}
+		cmd, err := editNotes(m.Data[key][idx])
+		if err != nil {
+			m.Err = err
+			return m, nil
+		}
+		return m, cmd
+	}
+	return m, nil
+}
+
+// handleNotesEdited stores notes written in the external editor. Trailing
+// newlines the editor adds are dropped; an empty file clears the notes.
+func (m Model) handleNotesEdited(msg notesEditedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.Err = fmt.Errorf("editing notes: %w", msg.err)
+		return m, nil
+	}
+	m.Err = nil
+	key, idx, ok := m.Data.locate(msg.id)
+	if !ok {
+		return m, nil
+	}
+	task := m.Data[key][idx]
+	notes := strings.TrimRight(msg.notes, " \t\r\n")
+	if task.Notes == notes {
+		return m, nil
+	}
+	m.recordUndo(fmt.Sprintf("edit notes for %q", task.Title))
+	m.Data[key][idx].Notes = notes
+	m.persist()
+	return m, nil
+}
+
+// editorCommand resolves the user's editor from $VISUAL, then $EDITOR,
+// falling back to vi. The variable may carry arguments, e.g. "code -w".
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// editNotes suspends the TUI and opens the task's notes in the user's editor
+// via a private temporary file.
+func editNotes(task Task) (tea.Cmd, error) {
+	f, err := os.CreateTemp("", "doitdoit-notes-*.md")
+	if err != nil {
+		return nil, fmt.Errorf("creating notes file: %w", err)
+	}
+	path := f.Name()
+	if task.Notes != "" {
+		_, err = f.WriteString(task.Notes + "\n")
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("writing notes file: %w", err)
+	}
+
+	editor := editorCommand()
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return notesEditedMsg{id: task.ID, err: err}
+		}
+		notes, err := os.ReadFile(path)
+		return notesEditedMsg{id: task.ID, notes: string(notes), err: err}
+	}), nil
+}
+
+// describeKey names the list a task is filed under, calling today's list
+// Today.
+func describeKey(key string, today time.Time) string {
+	switch key {
+	case FutureKey:
+		return "Future"
+	case today.Format(dateLayout):
+		return "Today"
+	}
+	if date, err := parseDate(key, today.Location()); err == nil {
+		return date.Format("Mon, Jan 02 2006")
+	}
+	return key
+}
+
+func (m Model) detailsModalView() string {
+	frame := m.modal(64, styles.Highlight)
+	innerWidth := frame.innerWidth()
+
+	key, idx, ok := m.Data.locate(m.detailID)
+	if !ok {
+		return frame.renderModal("Task details", "This task no longer exists.")
+	}
+	task := m.Data[key][idx]
+	today := m.Calendar.Today()
+
+	label := lipgloss.NewStyle().Foreground(styles.Subtle)
+	text := lipgloss.NewStyle().Foreground(styles.Text).Width(innerWidth)
+	field := func(name, value string) string {
+		return label.Render(fmt.Sprintf("%-10s", name)) + lipgloss.NewStyle().Foreground(styles.Text).Render(value)
+	}
+
+	status := "Open"
+	if task.Completed {
+		status = "Completed"
+	}
+	due := "None"
+	if task.DueDate != "" {
+		due = task.DueDate
+	}
+	history := []string{field("Status", status), field("List", describeKey(key, today)), field("Due", due)}
+	if task.Deadline != "" {
+		deadline := task.Deadline
+		if badge, _ := task.DeadlineBadge(today); badge != "" {
+			deadline += " (" + badge + ")"
+		}
+		history = append(history, field("Deadline", deadline))
+	}
+	if task.Repeat != "" {
+		history = append(history, field("Repeats", task.Repeat))
+	}
+	if task.FirstScheduled != "" {
+		planned := task.FirstScheduled
+		if date, err := parseDate(planned, today.Location()); err == nil {
+			planned = date.Format("Mon, Jan 02 2006")
+		}
+		history = append(history, field("Planned", planned))
+	}
+	if task.Rollovers > 0 {
+		rolled := "once"
+		if task.Rollovers > 1 {
+			rolled = fmt.Sprintf("%d times", task.Rollovers)
+		}
+		history = append(history, field("Rolled", rolled))
+	}
+	if !task.CreatedAt.IsZero() {
+		history = append(history, field("Created", task.CreatedAt.In(today.Location()).Format("Mon, Jan 02 2006 15:04")))
+	}
+
+	notes := label.Render("No notes yet.")
+	if task.Notes != "" {
+		notes = text.Render(task.Notes)
+	}
+
+	hint := "Press n to edit notes in $EDITOR, Esc to close"
+	if m.history {
+		hint = "History is read-only. Press Esc to close"
+	}
+
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		"",
+		lipgloss.JoinVertical(lipgloss.Left, history...),
+		"",
+		label.Render("Notes"),
+		notes,
+		"",
+		label.Render(hint),
+	)
+	return frame.renderModal(task.Title, body)
+}
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+func newDetailsTestModel(t *testing.T) Model {
+	t.Helper()
+	today := testNow().Format(dateLayout)
+	return Model{
+		Calendar: testCalendar(),
+		Data: TodoData{today: {
+			{ID: "1", Title: "Plan offsite", CreatedAt: time.Date(2026, 3, 4, 9, 30, 0, 0, time.Local), DueDate: today, Notes: "Book venue\nInvite the team"},
+			{ID: "2", Title: "Bare"},
+		}},
+		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
+		VisibleDays: 1,
+		State:       Browsing,
+		TextInput:   textinput.New(),
+		dateKeys:    []string{today},
+		width:       100,
+		height:      40,
+	}
+}
+
+func TestNotesOmittedFromJSONWhenEmpty(t *testing.T) {
+	bare, _ := json.Marshal(Task{ID: "1", Title: "Bare"})
+	if strings.Contains(string(bare), "notes") {
+		t.Errorf("expected empty notes to be omitted, got %s", bare)
+	}
+
+	var task Task
+	if err := json.Unmarshal([]byte(`{"id":"1","title":"T","notes":"line one\nline two"}`), &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Notes != "line one\nline two" {
+		t.Errorf("notes = %q", task.Notes)
+	}
+}
+
+func TestDetailsPaneShowsNotesAndDates(t *testing.T) {
+	m := pressRune(newDetailsTestModel(t), 'i')
+	if m.State != ViewingDetails || m.detailID != "1" {
+		t.Fatalf("expected details for the selected task, got state=%v id=%q", m.State, m.detailID)
+	}
+
+	view := m.View().Content
+	for _, want := range []string{"Plan offsite", "Book venue", "Invite the team", "Mar 04 2026", "Today", "Open"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected details to contain %q, got %q", want, view)
+		}
+	}
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	if m = updated.(Model); m.State != Browsing {
+		t.Fatalf("expected Esc to close details, got %v", m.State)
+	}
+
+	m.RowIdx = 1
+	m = pressRune(m, 'i')
+	if view := m.View().Content; !strings.Contains(view, "No notes yet") {
+		t.Errorf("expected an empty-notes hint, got %q", view)
+	}
+}
+
+func TestDetailsIgnoredWithoutTask(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	m := Model{Calendar: testCalendar(), Data: TodoData{today: {}}, VisibleDays: 1, State: Browsing, dateKeys: []string{today}}
+	if m = pressRune(m, 'i'); m.State != Browsing {
+		t.Fatalf("expected i on an empty day to do nothing, got %v", m.State)
+	}
+}
+
+func TestEditedNotesSaveToTheOpenedTask(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	m := pressRune(newDetailsTestModel(t), 'i')
+	m.RowIdx = 1 // the cursor moving must not redirect the edit
+
+	updated, _ := m.Update(notesEditedMsg{id: "1", notes: "Book venue\nOrder lunch\n\n"})
+	m = updated.(Model)
+
+	if got := m.Data[today][0].Notes; got != "Book venue\nOrder lunch" {
+		t.Fatalf("notes = %q, want trailing newlines trimmed", got)
+	}
+	loaded, err := Load(m.FilePath, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded[today][0].Notes != "Book venue\nOrder lunch" {
+		t.Errorf("notes were not persisted, got %q", loaded[today][0].Notes)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	m = pressRune(updated.(Model), 'u')
+	if got := m.Data[today][0].Notes; got != "Book venue\nInvite the team" {
+		t.Errorf("expected undo to restore the old notes, got %q", got)
+	}
+}
+
+func TestEditorFailureKeepsNotes(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	m := pressRune(newDetailsTestModel(t), 'i')
+	updated, _ := m.Update(notesEditedMsg{id: "1", err: errors.New("exit status 1")})
+	m = updated.(Model)
+	if m.Err == nil || m.Data[today][0].Notes != "Book venue\nInvite the team" || len(m.undoStack) != 0 {
+		t.Fatalf("expected editor error surfaced without changes, err=%v", m.Err)
+	}
+}
+
+func TestEditorCommandPrefersVisual(t *testing.T) {
+	t.Setenv("VISUAL", "code -w")
+	t.Setenv("EDITOR", "nano")
+	if got := strings.Join(editorCommand(), " "); got != "code -w" {
+		t.Errorf("editor = %q, want $VISUAL", got)
+	}
+	t.Setenv("VISUAL", "")
+	if got := strings.Join(editorCommand(), " "); got != "nano" {
+		t.Errorf("editor = %q, want $EDITOR", got)
+	}
+	t.Setenv("EDITOR", "")
+	if got := strings.Join(editorCommand(), " "); got != "vi" {
+		t.Errorf("editor = %q, want vi fallback", got)
+	}
+}
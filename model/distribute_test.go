@@ -19,7 +19,7 @@ func withTimeZone(t *testing.T, name string, offsetHours int) {
 // dayKey returns the YYYY-MM-DD key for today+offset using the same notion of
 // "today" the production code uses, so assertions stay in sync with it.
 func dayKey(offset int) string {
-	return startOfDay(time.Now()).AddDate(0, 0, offset).Format(dateLayout)
+	return testCalendar().Today().AddDate(0, 0, offset).Format(dateLayout)
 }
 
 // taskIDsByDate flattens the data into a date -> set-of-IDs view for assertions.
@@ -67,7 +67,7 @@ func TestDistributeFutureTasks(t *testing.T) {
 				},
 			}
 
-			data.DistributeFutureTasks(visibleDays)
+			data.DistributeFutureTasks(visibleDays, testCalendar().Today())
 
 			byDate := taskIDsByDate(data)
 
@@ -112,14 +112,14 @@ func TestDistributeFutureTasks(t *testing.T) {
 func TestDistributeFutureTasksNoFutureBucket(t *testing.T) {
 	// Missing bucket must not panic and must not create one spuriously.
 	data := TodoData{}
-	data.DistributeFutureTasks(3)
+	data.DistributeFutureTasks(3, testCalendar().Today())
 	if _, ok := data["Future"]; ok {
 		t.Errorf("did not expect a Future bucket to be created, got %v", data)
 	}
 
 	// Empty bucket is left exactly as-is.
 	empty := TodoData{"Future": []Task{}}
-	empty.DistributeFutureTasks(3)
+	empty.DistributeFutureTasks(3, testCalendar().Today())
 	if len(empty["Future"]) != 0 {
 		t.Errorf("expected Future to remain empty, got %v", empty["Future"])
 	}
@@ -152,7 +152,7 @@ func TestRollOverIncompleteTasksTimezone(t *testing.T) {
 				},
 			}
 
-			if !data.rollOverIncompleteTasks() {
+			if !data.rollOverIncompleteTasks(testCalendar().Today()) {
 				t.Fatal("expected rollover to report a change")
 			}
 
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+func newEditTestModel(t *testing.T) Model {
+	t.Helper()
+	today := testNow().Format(dateLayout)
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return Model{
+		Calendar: testCalendar(),
+		Data: TodoData{today: {
+			{ID: "1", Title: "Fix teh typo", CreatedAt: created, DueDate: today},
+			{ID: "2", Title: "Other"},
+		}},
+		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
+		VisibleDays: 1,
+		State:       Browsing,
+		TextInput:   textinput.New(),
+		dateKeys:    []string{today},
+	}
+}
+
+func TestEditRenamesInPlaceKeepingIdentity(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	m := newEditTestModel(t)
+	original := m.Data[today][0]
+
+	m = pressRune(m, 'e')
+	if m.State != Editing || m.TextInput.Value() != "Fix teh typo" {
+		t.Fatalf("expected prefilled editor, got state=%v value=%q", m.State, m.TextInput.Value())
+	}
+	m.width, m.height = 100, 30
+	if view := m.View().Content; !strings.Contains(view, "Fix teh typo") {
+		t.Fatalf("expected the input to render in place of the task, got %q", view)
+	}
+
+	m.TextInput.SetValue("  Fix the typo ")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.State != Browsing || m.Err != nil {
+		t.Fatalf("expected save to return to browsing, got state=%v err=%v", m.State, m.Err)
+	}
+	got := m.Data[today][0]
+	if got.Title != "Fix the typo" {
+		t.Fatalf("title = %q, want trimmed rename", got.Title)
+	}
+	if got.ID != original.ID || !got.CreatedAt.Equal(original.CreatedAt) || got.DueDate != original.DueDate {
+		t.Fatalf("rename must keep ID and dates, got %+v", got)
+	}
+
+	loaded, err := Load(m.FilePath, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded[today][0].Title != "Fix the typo" {
+		t.Errorf("rename was not persisted, got %v", loaded[today])
+	}
+
+	m = pressRune(m, 'u')
+	if got := m.Data[today][0]; got.Title != "Fix teh typo" {
+		t.Errorf("expected undo to restore the old title, got %q", got.Title)
+	}
+}
+
+func TestEditRejectsEmptyAndEscCancels(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	m := pressRune(newEditTestModel(t), 'e')
+
+	m.TextInput.SetValue("   ")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if m.State != Editing || m.Err == nil {
+		t.Fatalf("expected empty title to keep the editor open with an error, got state=%v", m.State)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	m = updated.(Model)
+	if m.State != Browsing || m.Err != nil || m.Data[today][0].Title != "Fix teh typo" {
+		t.Fatalf("expected Esc to cancel without changes, got state=%v title=%q", m.State, m.Data[today][0].Title)
+	}
+	if len(m.undoStack) != 0 {
+		t.Error("a cancelled edit should not create undo history")
+	}
+}
+
+func TestEditIgnoredWithoutTask(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	m := Model{Calendar: testCalendar(), Data: TodoData{today: {}}, VisibleDays: 1, State: Browsing, dateKeys: []string{today}}
+	if m = pressRune(m, 'e'); m.State != Browsing {
+		t.Fatalf("expected e on an empty day to do nothing, got %v", m.State)
+	}
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// Reopen marks the completed task at idx under key as incomplete. Past days
+// only ever hold completed history, so a task reopened there moves to today
+// with today's DueDate, just as rollover would move it on the next load. It
+// returns the key the task now lives under.
+func (d TodoData) Reopen(key string, idx int, cal Calendar) (string, bool) {
+	tasks := d[key]
+	if idx < 0 || idx >= len(tasks) || !tasks[idx].Completed {
+		return key, false
+	}
+	newIdx, _ := d.Toggle(key, idx, cal)
+
+	todayKey := cal.Today().Format(dateLayout)
+	if key == FutureKey || key >= todayKey {
+		return key, true
+	}
+	task, _ := d.Remove(key, newIdx)
+	if len(d[key]) == 0 {
+		delete(d, key)
+	}
+	task.DueDate = todayKey
+	d.Add(todayKey, task)
+	return todayKey, true
+}
+
+// CopyForward adds a fresh, incomplete copy of the task at idx under key to
+// today, leaving the original where it is. Recurrence is not copied: the
+// rule already lives on the task's next occurrence. The copy is created now
+// by cal.
+func (d TodoData) CopyForward(key string, idx int, cal Calendar) (Task, bool) {
+	tasks := d[key]
+	if idx < 0 || idx >= len(tasks) {
+		return Task{}, false
+	}
+	copied := NewTask(tasks[idx].Title, cal.Now())
+	copied.Notes = tasks[idx].Notes
+	d.Add(cal.Today().Format(dateLayout), copied)
+	return copied, true
+}
+
+// earliestPastDate returns the oldest day before today that holds tasks.
+func (d TodoData) earliestPastDate(today time.Time) (time.Time, bool) {
+	todayKey := today.Format(dateLayout)
+	earliest := ""
+	for key, tasks := range d {
+		if key == FutureKey || key >= todayKey || len(tasks) == 0 {
+			continue
+		}
+		if earliest == "" || key < earliest {
+			earliest = key
+		}
+	}
+	if earliest == "" {
+		return time.Time{}, false
+	}
+	date, err := parseDate(earliest, today.Location())
+	return date, err == nil
+}
+
+// enterHistory switches to the read-only history view with the window
+// ending on lastDay, or yesterday if lastDay is later, and focuses that day.
+func (m *Model) enterHistory(lastDay time.Time) {
+	yesterday := m.Calendar.Today().AddDate(0, 0, -1)
+	if lastDay.After(yesterday) {
+		lastDay = yesterday
+	}
+	m.history = true
+	m.ShowFuture = false
+	m.updateDateKeysFrom(lastDay.AddDate(0, 0, -(m.VisibleDays - 1)))
+	m.ColIdx = len(m.dateKeys) - 1
+	m.RowIdx = 0
+	m.clampRow()
+}
+
+func (m *Model) exitHistory() {
+	m.history = false
+	m.updateDateKeys()
+	m.ColIdx = 0
+	m.RowIdx = 0
+	m.clampRow()
+}
+
+// shiftHistoryWindow moves the history window to start on firstDay. It never
+// reaches today, which belongs to the normal view, and stops at the oldest
+// day with any history.
+func (m *Model) shiftHistoryWindow(firstDay time.Time) bool {
+	lastDay := firstDay.AddDate(0, 0, m.VisibleDays-1)
+	today := m.Calendar.Today()
+	if !lastDay.Before(today) {
+		return false
+	}
+	if firstDay.Before(m.firstVisibleDate()) {
+		if earliest, ok := m.Data.earliestPastDate(today); !ok || firstDay.Before(earliest) {
+			return false
+		}
+	}
+	m.updateDateKeysFrom(firstDay)
+	return true
+}
+
+// historyReadOnly explains why a key does nothing in the history view.
+const historyReadOnly = "History is read-only: space reopens, c copies to Today, H returns"
+
+// handleHistoryKey handles the keys that behave differently in history. Past
+// days are read-only apart from reopening a task or copying it forward; only
+// the browsing keys that cannot change a task fall through to the normal
+// handler, and anything else is refused.
+func (m Model) handleHistoryKey(msg tea.KeyPressMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "H":
+		m.exitHistory()
+	case "esc":
+		if m.filter != "" {
+			return m, nil, false
+		}
+		m.exitHistory()
+	case "f":
+		m.exitHistory()
+		return m, nil, false
+	case "enter", "space":
+		task, ok := m.selectedTask()
+		if !ok {
+			break
+		}
+		if !task.Completed {
+			m.status = "Only completed tasks can be reopened"
+			break
+		}
+		m.recordUndo(fmt.Sprintf("reopen %q", task.Title))
+		m.Data.Reopen(m.getCurrentKey(), m.RowIdx, m.Calendar)
+		m.clampRow()
+		m.status = fmt.Sprintf("Reopened %q on Today", task.Title)
+		m.persist()
+	case "c":
+		task, ok := m.selectedTask()
+		if !ok {
+			break
+		}
+		m.recordUndo(fmt.Sprintf("copy %q to Today", task.Title))
+		m.Data.CopyForward(m.getCurrentKey(), m.RowIdx, m.Calendar)
+		m.status = fmt.Sprintf("Copied %q to Today", task.Title)
+		m.persist()
+	case "right", "l", "left", "h", "up", "k", "down", "j",
+		"/", "#", "i", "y", "u", "ctrl+r", "q", "ctrl+c":
+		return m, nil, false
+	default:
+		m.status = historyReadOnly
+	}
+	return m, nil, true
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func newHistoryTestModel(t *testing.T) Model {
+	t.Helper()
+	day := func(offset int) string { return testNow().AddDate(0, 0, offset).Format(dateLayout) }
+	m := Model{
+		Calendar: testCalendar(),
+		Data: TodoData{
+			day(-6): {{ID: "oldest", Title: "File taxes", Completed: true}},
+			day(-1): {
+				{ID: "walk", Title: "Walk dog", Completed: true},
+				{ID: "call", Title: "Call plumber", Notes: "Ask about the boiler", Repeat: "daily", Completed: true},
+			},
+			day(0): {{ID: "today", Title: "Pay rent"}},
+		},
+		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
+		VisibleDays: 3,
+		State:       Browsing,
+		TextInput:   textinput.New(),
+		width:       120,
+		height:      40,
+	}
+	m.updateDateKeys()
+	return m
+}
+
+func TestHistoryShowsPastDaysAndScrollsToEarliest(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	yesterday := testNow().AddDate(0, 0, -1).Format(dateLayout)
+	m := pressRune(newHistoryTestModel(t), 'H')
+
+	if !m.history || m.dateKeys[len(m.dateKeys)-1] != yesterday || m.ColIdx != len(m.dateKeys)-1 {
+		t.Fatalf("expected history ending on yesterday, got %v col=%d", m.dateKeys, m.ColIdx)
+	}
+	view := ansi.Strip(m.View().Content)
+	if !strings.Contains(view, "Walk dog") || strings.Contains(view, "Pay rent") {
+		t.Fatalf("expected yesterday's completed tasks without today, got %q", view)
+	}
+
+	if m = pressRune(m, 'l'); m.dateKeys[len(m.dateKeys)-1] != yesterday {
+		t.Fatalf("history must not scroll into today, got %v", m.dateKeys)
+	}
+	for range 20 {
+		m = pressRune(m, 'h')
+	}
+	earliest := testNow().AddDate(0, 0, -6).Format(dateLayout)
+	if m.dateKeys[0] != earliest {
+		t.Fatalf("expected scrolling to stop at the earliest history %s, got %v", earliest, m.dateKeys)
+	}
+
+	m = pressRune(m, 'H')
+	if m.history || m.dateKeys[0] != today || m.ColIdx != 0 {
+		t.Fatalf("expected H to return to today, got history=%v %v", m.history, m.dateKeys)
+	}
+}
+
+func TestHistoryReopenMovesTaskToToday(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	yesterday := testNow().AddDate(0, 0, -1).Format(dateLayout)
+	m := pressRune(newHistoryTestModel(t), 'H')
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	m = updated.(Model)
+
+	if len(m.Data[yesterday]) != 1 || m.Data[yesterday][0].ID != "call" {
+		t.Fatalf("expected the reopened task to leave its past day, got %v", m.Data[yesterday])
+	}
+	reopened := m.Data[today][len(m.Data[today])-1]
+	if reopened.ID != "walk" || reopened.Completed || reopened.DueDate != today {
+		t.Fatalf("expected an incomplete task on today, got %+v", reopened)
+	}
+	if !strings.Contains(m.status, "Reopened") {
+		t.Errorf("expected a reopen note, got %q", m.status)
+	}
+
+	loaded, err := Load(m.FilePath, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, tasks := range loaded {
+		for _, task := range tasks {
+			if key < today && !task.Completed {
+				t.Errorf("past day %s holds incomplete task %q", key, task.Title)
+			}
+		}
+	}
+
+	if m = pressRune(m, 'u'); len(m.Data[yesterday]) != 2 || !m.history {
+		t.Errorf("expected undo to restore the past day in history, got %v", m.Data[yesterday])
+	}
+}
+
+func TestHistoryCopyForwardAndReadOnlyKeys(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	yesterday := testNow().AddDate(0, 0, -1).Format(dateLayout)
+	m := pressRune(pressRune(newHistoryTestModel(t), 'H'), 'j')
+
+	m = pressRune(m, 'c')
+	if len(m.Data[yesterday]) != 2 || !m.Data[yesterday][1].Completed {
+		t.Fatalf("copying must leave the original in place, got %v", m.Data[yesterday])
+	}
+	copied := m.Data[today][len(m.Data[today])-1]
+	if copied.Title != "Call plumber" || copied.ID == "call" || copied.Completed || copied.Notes != "Ask about the boiler" || copied.Repeat != "" {
+		t.Fatalf("expected a fresh copy with notes and no repeat, got %+v", copied)
+	}
+
+	for _, r := range "dJeDx" {
+		m = pressRune(m, r)
+		if m.State != Browsing || len(m.Data[yesterday]) != 2 || m.Data[yesterday][0].ID != "walk" {
+			t.Fatalf("%q must not change history, got state=%v %v", r, m.State, m.Data[yesterday])
+		}
+		if !strings.Contains(m.status, "read-only") {
+			t.Errorf("%q: expected a read-only note, got %q", r, m.status)
+		}
+	}
+
+	m = pressRune(m, 'i')
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: 'n', Text: "n"})
+	if m = updated.(Model); cmd != nil || m.State != ViewingDetails || !strings.Contains(m.status, "read-only") {
+		t.Fatalf("n must not edit notes in history, got state=%v status=%q", m.State, m.status)
+	}
+	m = pressRune(m, 'i')
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	if m = updated.(Model); m.history {
+		t.Error("expected Esc to leave history")
+	}
+}
@@ -2,15 +2,15 @@ package model
 
 import (
 	"testing"
-	"time"
 
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 )
 
 func TestAddingTask_Enter(t *testing.T) {
-	today := time.Now().Format("2006-01-02")
+	today := testNow().Format("2006-01-02")
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        make(TodoData),
 		VisibleDays: 3,
 		State:       Adding,
@@ -55,6 +55,7 @@ func TestAddingTask_Enter(t *testing.T) {
 
 func TestAddingTask_Esc(t *testing.T) {
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        make(TodoData),
 		VisibleDays: 3,
 		State:       Adding,
@@ -78,8 +79,9 @@ func TestAddingTask_Esc(t *testing.T) {
 }
 
 func TestInputConfiguredOnModeSwitch(t *testing.T) {
-	today := time.Now().Format("2006-01-02")
+	today := testNow().Format("2006-01-02")
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {}, "Future": {}},
 		VisibleDays: 1,
 		State:       Browsing,
@@ -0,0 +1,230 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/dtt101/doitdoit/lockfile"
+)
+
+// Problem is one integrity issue in task data.
+type Problem struct {
+	// Key is the list holding the problem and Title the task, if any.
+	Key   string
+	Title string
+	Issue string
+	// Fix describes what RepairFile does about it, or is empty when the
+	// problem needs a person to decide.
+	Fix string
+}
+
+// Diagnose checks task data for problems that the app's own writes never
+// produce but hand edits, other tools, and bad syncs can: unparseable date
+// keys, duplicate IDs, empty titles, malformed due dates in Future, completed
+// tasks filed above incomplete ones, and incomplete tasks left on past days.
+// Past days are those before today. Problems are ordered by list.
+func Diagnose(data TodoData, today time.Time) []Problem {
+	var problems []Problem
+	seen := make(map[string]placedTask)
+	for _, key := range data.SortedKeys() {
+		tasks := data[key]
+		day, err := parseDate(key, today.Location())
+		if key != FutureKey && err != nil {
+			problems = append(problems, Problem{
+				Key:   key,
+				Issue: fmt.Sprintf("list name is not a YYYY-MM-DD date, so its %d %s never roll over", len(tasks), pluralTasks(len(tasks))),
+				Fix:   "move its tasks to Future",
+			})
+		}
+
+		sawCompleted := false
+		for _, task := range tasks {
+			if first, ok := seen[task.ID]; ok {
+				fix := "give the later copy a new ID"
+				if samePlacedTask(first, placedTask{Key: key, Task: task}) {
+					fix = "remove the identical copy"
+				}
+				problems = append(problems, Problem{Key: key, Title: task.Title, Issue: fmt.Sprintf("ID %s is used more than once", task.ID), Fix: fix})
+			} else {
+				seen[task.ID] = placedTask{Key: key, Task: task}
+			}
+
+			if strings.TrimSpace(task.Title) == "" {
+				problem := Problem{Key: key, Issue: "task has no title"}
+				if removableUntitled(task) {
+					problem.Fix = "remove it"
+				}
+				problems = append(problems, problem)
+			}
+
+			if key == FutureKey && task.DueDate != "" {
+				if _, err := parseDate(task.DueDate, today.Location()); err != nil {
+					problems = append(problems, Problem{Key: key, Title: task.Title, Issue: fmt.Sprintf("due date %q is not a YYYY-MM-DD date", task.DueDate), Fix: "make it undated"})
+				}
+			}
+
+			if task.Completed {
+				sawCompleted = true
+			} else if sawCompleted {
+				problems = append(problems, Problem{Key: key, Title: task.Title, Issue: "incomplete task is filed below a completed one", Fix: "sink completed tasks to the bottom"})
+			}
+
+			if key != FutureKey && err == nil && day.Before(today) && !task.Completed {
+				problems = append(problems, Problem{Key: key, Title: task.Title, Issue: "incomplete task was left on a past day", Fix: "roll it over to today"})
+			}
+		}
+	}
+	return problems
+}
+
+// Inspect diagnoses the data file at path as it is on disk, without rolling
+// anything over first, counting days before today as past.
+func Inspect(path string, today time.Time) ([]Problem, error) {
+	data, err := loadRaw(path)
+	if err != nil {
+		return nil, err
+	}
+	return Diagnose(data, today), nil
+}
+
+// removableUntitled reports whether an untitled task carries nothing worth
+// keeping.
+func removableUntitled(task Task) bool {
+	return strings.TrimSpace(task.Notes) == "" && task.Repeat == ""
+}
+
+func pluralTasks(n int) string {
+	if n == 1 {
+		return "task"
+	}
+	return "tasks"
+}
+
+// repair fixes every problem Diagnose offers a Fix for, in an order where no
+// fix undoes another. It reports whether anything changed.
+func (d TodoData) repair(today time.Time) bool {
+	changed := false
+	for _, key := range d.SortedKeys() {
+		if _, err := parseDate(key, today.Location()); key != FutureKey && err != nil {
+			for _, task := range d[key] {
+				d.Add(FutureKey, task)
+			}
+			delete(d, key)
+			changed = true
+		}
+	}
+
+	seen := make(map[string]placedTask)
+	for _, key := range d.SortedKeys() {
+		tasks := d[key][:0]
+		for _, task := range d[key] {
+			if key == FutureKey && task.DueDate != "" {
+				if _, err := parseDate(task.DueDate, today.Location()); err != nil {
+					task.DueDate = ""
+					changed = true
+				}
+			}
+			if strings.TrimSpace(task.Title) == "" && removableUntitled(task) {
+				changed = true
+				continue
+			}
+			if first, ok := seen[task.ID]; ok {
+				changed = true
+				if samePlacedTask(first, placedTask{Key: key, Task: task}) {
+					continue
+				}
+				task.ID = freshID(seen)
+			}
+			seen[task.ID] = placedTask{Key: key, Task: task}
+			tasks = append(tasks, task)
+		}
+		d[key] = tasks
+	}
+
+	if d.rollOverIncompleteTasks(today) {
+		changed = true
+	}
+	for key, tasks := range d {
+		sorted := append(withCompletion(tasks, false), withCompletion(tasks, true)...)
+		if !reflect.DeepEqual(sorted, tasks) {
+			d[key] = sorted
+			changed = true
+		}
+	}
+	return changed
+}
+
+// withCompletion returns the tasks whose Completed matches completed, in
+// order.
+func withCompletion(tasks []Task, completed bool) []Task {
+	var matching []Task
+	for _, task := range tasks {
+		if task.Completed == completed {
+			matching = append(matching, task)
+		}
+	}
+	return matching
+}
+
+// freshID returns a new task ID not already in use.
+func freshID(inUse map[string]placedTask) string {
+	for {
+		id := newTaskID()
+		if _, ok := inUse[id]; !ok {
+			return id
+		}
+	}
+}
+
+// RepairReport describes what RepairFile did.
+type RepairReport struct {
+	// Fixed lists the problems that were repaired, and Remaining those that
+	// still need attention.
+	Fixed     []Problem
+	Remaining []Problem
+	// Backup is the ID of the backup taken before the repair, if one was
+	// needed.
+	Backup string
+}
+
+// RepairFile fixes what it safely can in the data file at path. The file is
+// backed up first, whatever the backup policy, so `doitdoit backup restore`
+// can undo the repair. That copy is the save's only backup, and nothing is
+// pruned; otherwise the repaired file is saved as opts asks. Incomplete
+// tasks left before today roll over onto it.
+func RepairFile(path string, today time.Time, opts SaveOptions) (RepairReport, error) {
+	unlock, err := lockDataFile(path, lockfile.DefaultTimeout)
+	if err != nil {
+		return RepairReport{}, err
+	}
+	defer unlock()
+
+	data, err := loadRaw(path)
+	if err != nil {
+		return RepairReport{}, err
+	}
+	var report RepairReport
+	for _, problem := range Diagnose(data, today) {
+		if problem.Fix != "" {
+			report.Fixed = append(report.Fixed, problem)
+		}
+	}
+	if !data.repair(today) {
+		report.Fixed = nil
+		report.Remaining = Diagnose(data, today)
+		return report, nil
+	}
+
+	report.Backup, err = keepCopy(path)
+	if err != nil {
+		return RepairReport{}, fmt.Errorf("backing up before repair: %w", err)
+	}
+	opts.Backups = BackupPolicy{}
+	if err := data.Save(path, opts); err != nil {
+		return RepairReport{}, err
+	}
+	report.Remaining = Diagnose(data, today)
+	return report, nil
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseFindsEachProblem(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	past := testNow().AddDate(0, 0, -3).Format(dateLayout)
+	data := TodoData{
+		past:  {{ID: "1", Title: "Stranded"}},
+		today: {{ID: "2", Title: "Done", Completed: true}, {ID: "3", Title: "Below"}, {ID: "3", Title: "Below"}},
+		FutureKey: {
+			{ID: "4", Title: "Odd date", DueDate: "next week"},
+			{ID: "5", Title: ""},
+			{ID: "6", Title: " ", Notes: "Details"},
+		},
+		"2026-13-45": {{ID: "7", Title: "Nowhere"}},
+	}
+
+	var issues []string
+	for _, problem := range Diagnose(data, testCalendar().Today()) {
+		issues = append(issues, problem.Issue+" -> "+problem.Fix)
+	}
+	got := strings.Join(issues, "\n")
+	for _, want := range []string{
+		"left on a past day -> roll it over to today",
+		"filed below a completed one -> sink completed tasks to the bottom",
+		"used more than once -> remove the identical copy",
+		`due date "next week" is not a YYYY-MM-DD date -> make it undated`,
+		"no title -> remove it",
+		"no title -> ",
+		"never roll over -> move its tasks to Future",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q among:\n%s", want, got)
+		}
+	}
+}
+
+func TestRepairLeavesOnlyManualProblems(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	data := TodoData{
+		today:      {{ID: "1", Title: "Done", Completed: true}, {ID: "1", Title: "Clash"}},
+		"tomorrow": {{ID: "2", Title: "Moved", DueDate: "soon"}},
+		FutureKey:  {{ID: "3", Title: "", Notes: "Unnamed but noted"}},
+	}
+	if !data.repair(testCalendar().Today()) {
+		t.Fatal("expected repairs")
+	}
+	if problems := Diagnose(data, testCalendar().Today()); len(problems) != 1 || problems[0].Fix != "" {
+		t.Fatalf("expected only the noted untitled task left, got %+v", problems)
+	}
+	if data[today][0].Title != "Clash" || data[today][0].ID == "1" {
+		t.Errorf("expected the clashing task renumbered above the completed one, got %v", data[today])
+	}
+	if future := data[FutureKey]; len(future) != 2 || future[1].DueDate != "" {
+		t.Errorf("expected the misfiled task undated in Future, got %v", future)
+	}
+	if data.repair(testCalendar().Today()) {
+		t.Error("a second repair should find nothing to do")
+	}
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Titles may carry #tag and +project tokens. They stay in the title as
+// written and are mirrored, lower-cased and without the sigil, into the
+// task's Tags and Projects so scripts reading the JSON need not re-parse.
+//
+// A token is a whitespace-separated word whose sigil is followed by a
+// letter, so "issue #42" and "+1" stay plain text. Trailing punctuation
+// ("see +launch.") is not part of the label.
+
+// LabelSpan is a run of title text, marked when it is a #tag or +project.
+type LabelSpan struct {
+	Text  string
+	Label bool
+}
+
+// parseLabel returns the sigil and name of a single word, if it is a label.
+func parseLabel(word string) (sigil byte, name string, ok bool) {
+	if len(word) < 2 || (word[0] != '#' && word[0] != '+') {
+		return 0, "", false
+	}
+	name = strings.TrimRightFunc(word[1:], func(r rune) bool {
+		return unicode.IsPunct(r) && r != '_' && r != '-'
+	})
+	first := []rune(name)
+	if len(first) == 0 || !unicode.IsLetter(first[0]) {
+		return 0, "", false
+	}
+	return word[0], name, true
+}
+
+// SplitLabels cuts a title into plain text and label spans, preserving every
+// byte so the spans can be rendered back to back.
+func SplitLabels(title string) []LabelSpan {
+	var spans []LabelSpan
+	plain := strings.Builder{}
+	flush := func() {
+		if plain.Len() > 0 {
+			spans = append(spans, LabelSpan{Text: plain.String()})
+			plain.Reset()
+		}
+	}
+
+	rest := title
+	for rest != "" {
+		start := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsSpace(r) })
+		if start < 0 {
+			plain.WriteString(rest)
+			break
+		}
+		plain.WriteString(rest[:start])
+		rest = rest[start:]
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		}
+		word := rest[:end]
+		rest = rest[end:]
+
+		if sigil, name, ok := parseLabel(word); ok {
+			flush()
+			labelLen := 1 + len(name)
+			spans = append(spans, LabelSpan{Text: string(sigil) + name, Label: true})
+			plain.WriteString(word[labelLen:])
+			continue
+		}
+		plain.WriteString(word)
+	}
+	flush()
+	return spans
+}
+
+// ParseLabels returns the distinct tags and projects named in a title, in
+// order of first appearance.
+func ParseLabels(title string) (tags, projects []string) {
+	for _, word := range strings.Fields(title) {
+		sigil, name, ok := parseLabel(word)
+		if !ok {
+			continue
+		}
+		name = strings.ToLower(name)
+		if sigil == '#' && !slices.Contains(tags, name) {
+			tags = append(tags, name)
+		} else if sigil == '+' && !slices.Contains(projects, name) {
+			projects = append(projects, name)
+		}
+	}
+	return tags, projects
+}
+
+// SyncLabels refreshes Tags and Projects from the title. Call it whenever the
+// title changes.
+func (t *Task) SyncLabels() {
+	t.Tags, t.Projects = ParseLabels(t.Title)
+}
+
+// HasTag reports whether the task carries the tag, ignoring case and an
+// optional leading #.
+func (t Task) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, strings.ToLower(strings.TrimPrefix(tag, "#")))
+}
+
+// HasProject reports whether the task belongs to the project, ignoring case
+// and an optional leading +.
+func (t Task) HasProject(project string) bool {
+	return slices.Contains(t.Projects, strings.ToLower(strings.TrimPrefix(project, "+")))
+}
+
+// MatchesFilter reports whether the task matches a "#tag" or "+project"
+// filter; a bare word matches either. An empty filter matches everything.
+func (t Task) MatchesFilter(filter string) bool {
+	switch {
+	case filter == "":
+		return true
+	case strings.HasPrefix(filter, "#"):
+		return t.HasTag(filter)
+	case strings.HasPrefix(filter, "+"):
+		return t.HasProject(filter)
+	default:
+		return t.HasTag(filter) || t.HasProject(filter)
+	}
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		title          string
+		tags, projects []string
+	}{
+		{"Ship #Release notes +Launch.", []string{"release"}, []string{"launch"}},
+		{"Fix issue #42, +1 it", nil, nil},
+		{"#a-b_c #A-B_C (+ops)", []string{"a-b_c"}, nil},
+		{"Pair with C# folks +infra +Infra", nil, []string{"infra"}},
+	}
+	for _, tt := range tests {
+		tags, projects := ParseLabels(tt.title)
+		if !slices.Equal(tags, tt.tags) || !slices.Equal(projects, tt.projects) {
+			t.Errorf("ParseLabels(%q) = %v, %v; want %v, %v", tt.title, tags, projects, tt.tags, tt.projects)
+		}
+	}
+}
+
+func TestSplitLabelsPreservesTitle(t *testing.T) {
+	title := "  Ship #release, then +launch!  "
+	var rebuilt strings.Builder
+	var labels []string
+	for _, span := range SplitLabels(title) {
+		rebuilt.WriteString(span.Text)
+		if span.Label {
+			labels = append(labels, span.Text)
+		}
+	}
+	if rebuilt.String() != title {
+		t.Errorf("spans rebuilt %q, want %q", rebuilt.String(), title)
+	}
+	if !slices.Equal(labels, []string{"#release", "+launch"}) {
+		t.Errorf("labels = %v", labels)
+	}
+}
+
+func TestLabelsStoredExplicitlyAndResyncedOnLoad(t *testing.T) {
+	task := NewTask("Draft agenda #meeting +offsite", testNow())
+	encoded, _ := json.Marshal(task)
+	if !strings.Contains(string(encoded), `"tags":["meeting"]`) || !strings.Contains(string(encoded), `"projects":["offsite"]`) {
+		t.Fatalf("expected parsed fields in JSON, got %s", encoded)
+	}
+
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	today := testNow().Format(dateLayout)
+	stale := `{"` + today + `":[{"id":"1","title":"Renamed #new","tags":["old"]}]}`
+	if err := os.WriteFile(path, []byte(stale), 0600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := Load(path, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := data[today][0].Tags; !slices.Equal(got, []string{"new"}) {
+		t.Errorf("expected labels re-derived from the title, got %v", got)
+	}
+}
+
+func TestMatchesFilter(t *testing.T) {
+	task := NewTask("Review budget #finance +q3", testNow())
+	for filter, want := range map[string]bool{
+		"": true, "#finance": true, "#FINANCE": true, "+q3": true, "q3": true, "finance": true,
+		"+finance": false, "#q3": false, "ops": false,
+	} {
+		if got := task.MatchesFilter(filter); got != want {
+			t.Errorf("MatchesFilter(%q) = %v, want %v", filter, got, want)
+		}
+	}
+}
+
+func TestFilterNarrowsColumnsAndNavigation(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	tomorrow := testNow().AddDate(0, 0, 1).Format(dateLayout)
+	data := TodoData{
+		today: {
+			NewTask("Email landlord", testNow()),
+			NewTask("Write spec +launch", testNow()),
+			NewTask("Gym", testNow()),
+			NewTask("Book demo room +launch", testNow()),
+		},
+		tomorrow: {NewTask("Dentist", testNow())},
+	}
+	m := Model{
+		Calendar:    testCalendar(),
+		Data:        data,
+		VisibleDays: 2,
+		State:       Browsing,
+		TextInput:   textinput.New(),
+		dateKeys:    []string{today, tomorrow},
+		width:       120,
+		height:      40,
+	}
+
+	m = pressRune(m, '#')
+	m.TextInput.SetValue("+Launch")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if m.filter != "+launch" || m.RowIdx != 1 {
+		t.Fatalf("expected filter set and cursor on the first match, got filter=%q row=%d", m.filter, m.RowIdx)
+	}
+
+	view := m.View().Content
+	if strings.Contains(view, "Email landlord") || strings.Contains(view, "Dentist") || !strings.Contains(view, "Book demo room") {
+		t.Fatalf("expected only +launch tasks in every column, got %q", view)
+	}
+	if !strings.Contains(view, "No matching tasks") {
+		t.Errorf("expected an empty-filter hint for tomorrow, got %q", view)
+	}
+
+	if m = pressRune(m, 'j'); m.RowIdx != 3 {
+		t.Fatalf("expected j to skip hidden tasks, got row %d", m.RowIdx)
+	}
+	if m = pressRune(m, 'K'); m.Data[today][1].Title != "Book demo room +launch" || m.Data[today][2].Title != "Gym" {
+		t.Fatalf("expected reorder to swap with the previous match only, got %v", m.Data[today])
+	}
+
+	m = pressRune(m, 'l')
+	if _, ok := m.selectedTask(); ok {
+		t.Fatal("a column with no matches should select nothing")
+	}
+	if m = pressRune(m, 'd'); len(m.Data[tomorrow]) != 1 {
+		t.Fatal("delete must not touch a hidden task")
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	if m = updated.(Model); m.filter != "" {
+		t.Fatalf("expected Esc to clear the filter, got %q", m.filter)
+	}
+}
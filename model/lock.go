@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dtt101/doitdoit/lockfile"
+)
+
+// persistLockTimeout bounds how long a TUI save waits for another writer.
+// It runs on the update loop, so it is kept well below the CLI's patience.
+const persistLockTimeout = 500 * time.Millisecond
+
+// lockDataFile takes the advisory write lock beside the data file at path and
+// returns its release. A directory that does not exist or cannot be written
+// holds no file this process could change, so it proceeds unlocked and
+// leaves any error to the save.
+func lockDataFile(path string, timeout time.Duration) (func(), error) {
+	lock, err := lockfile.Acquire(path, timeout)
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+		return func() {}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return func() { lock.Release() }, nil
+}
+
+// Update runs one load-change-save cycle on the data file at path while
+// holding its lock, so concurrent writers serialise instead of interleaving.
+// The data is loaded as by Load as of today; change reports whether it
+// modified it, and only then is it saved as opts asks. Errors from change
+// are returned as they are.
+func Update(path string, retentionDays int, today time.Time, opts SaveOptions, change func(TodoData) (bool, error)) error {
+	unlock, err := lockDataFile(path, lockfile.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := load(path, retentionDays, today, opts)
+	if err != nil {
+		return fmt.Errorf("loading tasks: %w", err)
+	}
+	changed, err := change(data)
+	if err != nil || !changed {
+		return err
+	}
+	// An AfterSave error is returned as it is: the tasks were saved.
+	err = data.Save(path, opts)
+	if err != nil && !errors.Is(err, ErrAfterSave) {
+		err = fmt.Errorf("saving tasks: %w", err)
+	}
+	return err
+}
+
+// Preview runs change on the data file at path after the same load as
+// Update, but saves nothing, so a dry run shows exactly what Update would do.
+func Preview(path string, retentionDays int, today time.Time, change func(TodoData) (bool, error)) error {
+	data, _, err := loadTidied(path, retentionDays, today)
+	if err != nil {
+		return fmt.Errorf("loading tasks: %w", err)
+	}
+	_, err = change(data)
+	return err
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dtt101/doitdoit/lockfile"
+)
+
+func TestConcurrentUpdatesSerialise(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	today := testNow().Format(dateLayout)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			err := Update(path, 0, testCalendar().Today(), SaveOptions{}, func(data TodoData) (bool, error) {
+				data.Add(today, NewTask(fmt.Sprintf("Task %d", i), testNow()))
+				return true, nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	data, err := Load(path, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data[today]) != 8 {
+		t.Errorf("expected every writer's task, got %d", len(data[today]))
+	}
+}
+
+func TestPersistWaitsForLockAndReportsBusyFile(t *testing.T) {
+	m := newReloadTestModel(t)
+	m.Data[testNow().Format(dateLayout)] = []Task{NewTask("Held back", testNow())}
+
+	lock, err := lockfile.Acquire(m.FilePath, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.persist()
+	if m.Err == nil {
+		t.Fatal("expected a busy lock to surface as an error")
+	}
+
+	lock.Release()
+	m.persist()
+	if m.Err != nil {
+		t.Fatalf("expected the save to succeed once the lock is free, got %v", m.Err)
+	}
+	if data, _ := Load(m.FilePath, 0, testCalendar().Today(), SaveOptions{}); len(data) != 1 {
+		t.Errorf("expected the held-back task saved, got %v", data)
+	}
+}
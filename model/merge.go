@@ -0,0 +1,210 @@
+package model
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+)
+
+// placedTask is a task together with the list that holds it.
+type placedTask struct {
+	Key  string
+	Task Task
+}
+
+// mergeConflict is a task changed incompatibly on both sides of a merge, or
+// edited on one side and deleted on the other. The merge keeps one version
+// and Other holds the alternative; a nil side means the task was deleted.
+type mergeConflict struct {
+	ID    string
+	Kept  *placedTask
+	Other *placedTask
+}
+
+// Title names the conflicting task from whichever version still has it.
+func (c mergeConflict) Title() string {
+	if c.Kept != nil {
+		return c.Kept.Task.Title
+	}
+	return c.Other.Task.Title
+}
+
+// mergeData merges ours and theirs, two versions that each started from base,
+// task by task. A change made on only one side wins. When both sides change
+// the same field of one task differently, ours is kept and the conflict is
+// reported; a task edited on one side and deleted on the other is kept, so
+// an edit is never silently lost. Each list keeps the order of whichever side
+// reordered it, with the other side's additions placed after the task they
+// followed there.
+func mergeData(base, ours, theirs TodoData) (TodoData, []mergeConflict) {
+	baseTasks, ourTasks, theirTasks := indexByID(base), indexByID(ours), indexByID(theirs)
+
+	final := make(map[string]placedTask)
+	var conflicts []mergeConflict
+	ids := make(map[string]bool)
+	for id := range ourTasks {
+		ids[id] = true
+	}
+	for id := range theirTasks {
+		ids[id] = true
+	}
+	for id := range ids {
+		b, inBase := baseTasks[id]
+		o, inOurs := ourTasks[id]
+		t, inTheirs := theirTasks[id]
+
+		switch {
+		case inOurs && inTheirs:
+			merged, alternative, conflicted := mergeTask(b, o, t)
+			final[id] = merged
+			if conflicted {
+				conflicts = append(conflicts, mergeConflict{ID: id, Kept: &merged, Other: &alternative})
+			}
+		case inOurs:
+			if !inBase {
+				final[id] = o
+			} else if !samePlacedTask(o, b) {
+				final[id] = o
+				conflicts = append(conflicts, mergeConflict{ID: id, Kept: &o})
+			}
+		case inTheirs:
+			if !inBase {
+				final[id] = t
+			} else if !samePlacedTask(t, b) {
+				final[id] = t
+				conflicts = append(conflicts, mergeConflict{ID: id, Kept: &t})
+			}
+		}
+	}
+
+	merged := make(TodoData)
+	keys := make(map[string]bool)
+	for key := range ours {
+		keys[key] = true
+	}
+	for key := range theirs {
+		keys[key] = true
+	}
+	for key := range keys {
+		primary, secondary := ours[key], theirs[key]
+		if slices.Equal(taskIDs(primary), taskIDs(base[key])) {
+			primary, secondary = secondary, primary
+		}
+		if tasks := layoutList(key, primary, secondary, final); len(tasks) > 0 {
+			merged[key] = tasks
+		}
+	}
+
+	slices.SortFunc(conflicts, func(a, b mergeConflict) int { return cmp.Compare(a.ID, b.ID) })
+	return merged, conflicts
+}
+
+// mergeTask merges one task present on both sides, field by field against
+// base. It returns the merged task, the alternative that takes theirs for
+// every conflicting field, and whether there was any conflict. A task's list
+// and due date move together as its placement.
+func mergeTask(base, ours, theirs placedTask) (placedTask, placedTask, bool) {
+	merged, alternative := ours, ours
+	conflicted := false
+
+	ourPlace, theirPlace, basePlace := placement(ours), placement(theirs), placement(base)
+	switch {
+	case ourPlace == basePlace:
+		merged.Key, merged.Task.DueDate = theirs.Key, theirs.Task.DueDate
+		alternative.Key, alternative.Task.DueDate = theirs.Key, theirs.Task.DueDate
+	case theirPlace != basePlace && theirPlace != ourPlace:
+		conflicted = true
+		alternative.Key, alternative.Task.DueDate = theirs.Key, theirs.Task.DueDate
+	}
+
+	b := reflect.ValueOf(base.Task)
+	o := reflect.ValueOf(ours.Task)
+	t := reflect.ValueOf(theirs.Task)
+	m := reflect.ValueOf(&merged.Task).Elem()
+	a := reflect.ValueOf(&alternative.Task).Elem()
+	for i := range o.NumField() {
+		switch o.Type().Field(i).Name {
+		case "ID", "DueDate", "Tags", "Projects":
+			// Identity, placement, and labels derived from the title.
+			continue
+		}
+		bf, of, tf := b.Field(i).Interface(), o.Field(i).Interface(), t.Field(i).Interface()
+		switch {
+		case sameJSON(of, bf):
+			m.Field(i).Set(t.Field(i))
+			a.Field(i).Set(t.Field(i))
+		case sameJSON(tf, bf) || sameJSON(tf, of):
+		default:
+			conflicted = true
+			a.Field(i).Set(t.Field(i))
+		}
+	}
+	merged.Task.SyncLabels()
+	alternative.Task.SyncLabels()
+	return merged, alternative, conflicted
+}
+
+func placement(p placedTask) [2]string {
+	return [2]string{p.Key, p.Task.DueDate}
+}
+
+func samePlacedTask(a, b placedTask) bool {
+	return a.Key == b.Key && sameJSON(a.Task, b.Task)
+}
+
+// layoutList builds the merged list for key: the primary side's order, then
+// any task that only the secondary side files under key, placed after its
+// nearest preceding neighbour there.
+func layoutList(key string, primary, secondary []Task, final map[string]placedTask) []Task {
+	var tasks []Task
+	placed := make(map[string]bool)
+	for _, task := range primary {
+		if f, ok := final[task.ID]; ok && f.Key == key && !placed[task.ID] {
+			tasks = append(tasks, f.Task)
+			placed[task.ID] = true
+		}
+	}
+	for i, task := range secondary {
+		f, ok := final[task.ID]
+		if !ok || f.Key != key || placed[task.ID] {
+			continue
+		}
+		at := 0
+		for j := i - 1; j >= 0; j-- {
+			if idx := indexOfID(tasks, secondary[j].ID); idx >= 0 {
+				at = idx + 1
+				break
+			}
+		}
+		tasks = insertAt(tasks, at, f.Task)
+		placed[task.ID] = true
+	}
+	return tasks
+}
+
+func indexByID(data TodoData) map[string]placedTask {
+	index := make(map[string]placedTask)
+	for key, tasks := range data {
+		for _, task := range tasks {
+			index[task.ID] = placedTask{Key: key, Task: task}
+		}
+	}
+	return index
+}
+
+func taskIDs(tasks []Task) []string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func indexOfID(tasks []Task, id string) int {
+	for i, task := range tasks {
+		if task.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestMergeDataCombinesIndependentChanges(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	base := TodoData{today: {
+		{ID: "a", Title: "Buy milk"},
+		{ID: "b", Title: "Call mum"},
+		{ID: "c", Title: "Gone elsewhere"},
+	}}
+	ours := TodoData{today: {
+		{ID: "a", Title: "Buy oat milk"},
+		{ID: "b", Title: "Call mum"},
+		{ID: "c", Title: "Gone elsewhere"},
+		{ID: "d", Title: "Added here"},
+	}}
+	theirs := TodoData{
+		today: {
+			{ID: "e", Title: "Added on phone"},
+			{ID: "a", Title: "Buy milk"},
+		},
+		FutureKey: {{ID: "b", Title: "Call mum", Notes: "Birthday plans"}},
+	}
+
+	merged, conflicts := mergeData(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %+v", conflicts)
+	}
+	if got := strings.Join(taskIDs(merged[today]), ","); got != "e,a,d" {
+		t.Fatalf("today = %s, want e,a,d", got)
+	}
+	if merged[today][1].Title != "Buy oat milk" {
+		t.Errorf("expected our rename kept, got %q", merged[today][1].Title)
+	}
+	if future := merged[FutureKey]; len(future) != 1 || future[0].Notes != "Birthday plans" {
+		t.Errorf("expected their move and notes kept, got %v", future)
+	}
+}
+
+func TestMergeDataReportsConflicts(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	base := TodoData{today: {
+		{ID: "a", Title: "Book flights"},
+		{ID: "b", Title: "Pay invoice"},
+	}}
+	ours := TodoData{today: {
+		{ID: "a", Title: "Book flights to Oslo", Completed: true},
+	}}
+	theirs := TodoData{today: {
+		{ID: "a", Title: "Book flights to Bergen", Notes: "Window seat"},
+		{ID: "b", Title: "Pay invoice #finance"},
+	}}
+
+	merged, conflicts := mergeData(base, ours, theirs)
+	if len(conflicts) != 2 {
+		t.Fatalf("expected two conflicts, got %+v", conflicts)
+	}
+
+	edited := conflicts[0]
+	if edited.Kept.Task.Title != "Book flights to Oslo" || edited.Other.Task.Title != "Book flights to Bergen" {
+		t.Errorf("expected ours kept and theirs set aside, got %+v / %+v", edited.Kept.Task, edited.Other.Task)
+	}
+	if !edited.Kept.Task.Completed || edited.Kept.Task.Notes != "Window seat" {
+		t.Errorf("non-conflicting fields should merge, got %+v", edited.Kept.Task)
+	}
+
+	deleted := conflicts[1]
+	if deleted.Other != nil || deleted.Kept.Task.Title != "Pay invoice #finance" {
+		t.Errorf("expected the edit kept over our delete, got %+v", deleted)
+	}
+	if got := strings.Join(taskIDs(merged[today]), ","); got != "a,b" {
+		t.Errorf("today = %s, want a,b", got)
+	}
+}
+
+func TestPersistMergesExternalEditAndReviewsConflict(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := (TodoData{today: {{ID: "a", Title: "Water plants"}, {ID: "b", Title: "Stretch"}}}).Save(path, SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewModelWithRetention(path, 3, 0, testCalendar(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.width, m.height = 120, 40
+
+	// The web app renames both tasks before the reload ticker notices.
+	external := `{"` + today + `":[{"id":"a","title":"Water the ferns"},{"id":"b","title":"Stretch for 10 minutes"}]}`
+	if err := os.WriteFile(path, []byte(external), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+
+	m.TextInput = textinput.New()
+	m = pressRune(m, 'e')
+	m.TextInput.SetValue("Water the cactus")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+
+	loaded, err := Load(path, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded[today][0].Title != "Water the cactus" || loaded[today][1].Title != "Stretch for 10 minutes" {
+		t.Fatalf("expected our edit merged with theirs, got %v", loaded[today])
+	}
+	if len(m.conflicts) != 1 || !strings.Contains(m.status, "press C") {
+		t.Fatalf("expected one conflict surfaced, got %d and %q", len(m.conflicts), m.status)
+	}
+
+	m = pressRune(m, 'C')
+	if m.State != ResolvingConflicts || !strings.Contains(m.View().Content, "Water the ferns") {
+		t.Fatalf("expected the conflict modal with their version, got state=%v", m.State)
+	}
+	m = pressRune(m, 'o')
+	if m.State != Browsing || len(m.conflicts) != 0 {
+		t.Fatalf("expected the review to finish, got state=%v conflicts=%d", m.State, len(m.conflicts))
+	}
+	loaded, _ = Load(path, 0, testCalendar().Today(), SaveOptions{})
+	if loaded[today][0].Title != "Water the ferns" && loaded[today][1].Title != "Water the ferns" {
+		t.Errorf("expected their version saved, got %v", loaded[today])
+	}
+}
+
+func TestReloadedRewriteDoesNotResurrectDeletedTask(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := (TodoData{}).Save(path, SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewModelWithRetention(path, 3, 0, testCalendar(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Data.Add(today, NewTask("Water plants", testNow()))
+	m.persist()
+	if m.Err != nil {
+		t.Fatal(m.Err)
+	}
+
+	// A sync client rewrites the same content, then the web app deletes the
+	// task. Neither side edited it, so the delete must win quietly.
+	rewrite := func(content []byte, at time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, content, 0600); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, at, at)
+		updated, _ := m.Update(checkDataFile(path, m.dataModTime, m.dataSize)())
+		m = updated.(Model)
+	}
+	same, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewrite(same, time.Now().Add(time.Minute))
+	rewrite([]byte(`{}`), time.Now().Add(2*time.Minute))
+
+	if len(m.Data[today]) != 0 || len(m.conflicts) != 0 {
+		t.Fatalf("expected the delete applied without conflicts, got %v and %d conflicts", m.Data[today], len(m.conflicts))
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -18,6 +19,13 @@ const (
 	Adding
 	ChoosingMoveDestination
 	SettingMoveDate
+	Editing
+	ViewingDetails
+	SettingRepeat
+	SettingDeadline
+	Filtering
+	Searching
+	ResolvingConflicts
 )
 
 // moveTarget is either an exact calendar date or the undated Future list.
@@ -26,13 +34,6 @@ type moveTarget struct {
 	Future bool
 }
 
-type moveUndoSnapshot struct {
-	Data       TodoData
-	ShowFuture bool
-	ColIdx     int
-	RowIdx     int
-}
-
 type Model struct {
 	Data        TodoData
 	FilePath    string
@@ -40,6 +41,15 @@ type Model struct {
 	// RetentionDays is zero for forever and positive for pruning completed
 	// history older than that many days.
 	RetentionDays int
+	// SaveOptions are followed by every save, such as how many backups to
+	// keep.
+	SaveOptions SaveOptions
+	// Calendar decides what day it is; the zero value follows the system
+	// clock.
+	Calendar Calendar
+	// StaleAfter is how many rollovers a task may take before it is marked
+	// stale; zero turns the mark off.
+	StaleAfter int
 
 	// Navigation
 	ColIdx int
@@ -57,6 +67,14 @@ type Model struct {
 	// tell external writes apart from our own.
 	dataModTime time.Time
 	dataSize    int64
+	// base is the data as last read from or written to the file: the common
+	// ancestor when merging our changes with an external writer's.
+	base TodoData
+	// conflicts are merge results awaiting review, oldest first.
+	conflicts []mergeConflict
+	// conflictedCopies are sync-conflict siblings of the data file, which
+	// only `doitdoit doctor conflicts` folds back in.
+	conflictedCopies []string
 
 	// Terminal dimensions
 	width  int
@@ -67,7 +85,19 @@ type Model struct {
 
 	// Future View
 	ShowFuture bool
-	ShowHelp   bool
+	// history shows the read-only window of past days.
+	history  bool
+	ShowHelp bool
+
+	// detailID is the task shown in the detail pane.
+	detailID string
+
+	// filter narrows every column to tasks matching a "#tag" or "+project".
+	filter string
+
+	// Incremental search results and the highlighted one.
+	searchResults []searchResult
+	searchIdx     int
 
 	// Brief flash on copy
 	copyFlash bool
@@ -76,23 +106,30 @@ type Model struct {
 	brandFrame       int
 	brandAnimationID uint64
 
-	// Session-only move history.
+	// Session-only move and undo history.
 	lastMoveTarget *moveTarget
-	moveUndo       *moveUndoSnapshot
+	undoStack      []undoEntry
+	redoStack      []undoEntry
+
+	// status is a one-shot footer message, cleared by the next key press.
+	status string
 }
 
 func NewModel(filePath string, visibleDays int) (Model, error) {
-	return NewModelWithRetention(filePath, visibleDays, 0)
+	return NewModelWithRetention(filePath, visibleDays, 0, Calendar{}, SaveOptions{})
 }
 
 // NewModelWithRetention creates a model after applying the explicit retention
 // period selected by the user. Zero means completed history is kept forever.
-func NewModelWithRetention(filePath string, visibleDays, retentionDays int) (Model, error) {
+// cal decides what day it is for the session, and every save the model
+// makes follows opts.
+func NewModelWithRetention(filePath string, visibleDays, retentionDays int, cal Calendar, opts SaveOptions) (Model, error) {
 	if visibleDays < 1 {
 		return Model{}, fmt.Errorf("visible days must be at least 1")
 	}
 
-	data, err := Load(filePath, retentionDays)
+	today := cal.Today()
+	data, err := Load(filePath, retentionDays, today, opts)
 	if err != nil {
 		return Model{}, err
 	}
@@ -102,14 +139,18 @@ func NewModelWithRetention(filePath string, visibleDays, retentionDays int) (Mod
 		FilePath:      filePath,
 		VisibleDays:   visibleDays,
 		RetentionDays: retentionDays,
+		SaveOptions:   opts,
+		Calendar:      cal,
 		State:         Browsing,
 		TextInput:     textinput.New(),
-		todayKey:      time.Now().Format(dateLayout),
+		todayKey:      today.Format(dateLayout),
 	}
 	m.configureTextInput("New task...")
-	m.Data.DistributeFutureTasks(visibleDays)
+	m.base = cloneTodoData(data)
+	m.Data.DistributeFutureTasks(visibleDays, today)
 	m.updateDateKeys()
 	m.trackFileState()
+	m.conflictedCopies, _ = ConflictedCopies(filePath)
 	return m, nil
 }
 
@@ -124,7 +165,7 @@ func (m *Model) trackFileState() {
 
 func (m *Model) updateDateKeys() {
 	// Reset the viewport to the next N days starting from today.
-	today := startOfDay(time.Now())
+	today := m.Calendar.Today()
 	m.todayKey = today.Format(dateLayout)
 	m.updateDateKeysFrom(today)
 }
@@ -139,12 +180,13 @@ func (m *Model) updateDateKeysFrom(firstDay time.Time) {
 }
 
 func (m Model) firstVisibleDate() time.Time {
+	today := m.Calendar.Today()
 	if len(m.dateKeys) > 0 {
-		if date, err := parseDate(m.dateKeys[0]); err == nil {
+		if date, err := parseDate(m.dateKeys[0], today.Location()); err == nil {
 			return date
 		}
 	}
-	return startOfDay(time.Now())
+	return today
 }
 
 func (m Model) lastVisibleDate() time.Time {
@@ -155,7 +197,10 @@ func (m Model) lastVisibleDate() time.Time {
 // today, so navigation is infinite in the forward direction only.
 func (m *Model) shiftDateWindow(days int) bool {
 	firstDay := m.firstVisibleDate().AddDate(0, 0, days)
-	if firstDay.Before(startOfDay(time.Now())) {
+	if m.history {
+		return m.shiftHistoryWindow(firstDay)
+	}
+	if firstDay.Before(m.Calendar.Today()) {
 		return false
 	}
 	m.updateDateKeysFrom(firstDay)
@@ -166,18 +211,47 @@ func (m Model) Init() tea.Cmd {
 	return tea.Batch(textinput.Blink, dateTick(), reloadTick())
 }
 
-// persist is last-writer-wins: an external edit landing between the previous
-// reload check and this save is overwritten, the same trade-off the web app
-// makes. The reload ticker keeps that window to a few seconds.
+// persist saves the data. An external edit landing between the previous
+// reload check and this save is merged in first, against the base snapshot,
+// rather than overwritten. The file's lock is held from that check to the
+// save; if another writer keeps it too long the save fails and the changes
+// stay in memory for the next one.
 func (m *Model) persist() {
-	if err := m.Data.Save(m.FilePath); err != nil {
+	unlock, err := lockDataFile(m.FilePath, persistLockTimeout)
+	if err != nil {
 		m.Err = err
 		return
 	}
-	m.Err = nil
+	defer unlock()
+
+	if m.base != nil && m.fileChangedOnDisk() {
+		if disk, err := loadRaw(m.FilePath); err == nil {
+			m.applyReloadedData(disk)
+		}
+	}
+	// An error after the save is shown, but the file was still written.
+	err = m.Data.Save(m.FilePath, m.SaveOptions)
+	m.Err = err
+	if err != nil && !errors.Is(err, ErrAfterSave) {
+		return
+	}
+	if m.base != nil {
+		m.base = cloneTodoData(m.Data)
+	}
 	m.trackFileState()
 }
 
+// fileChangedOnDisk reports whether the data file differs from the state
+// last tracked. A missing file is not a change: a sync client may be
+// mid-replace, and merging against nothing would delete everything.
+func (m Model) fileChangedOnDisk() bool {
+	fi, err := os.Stat(m.FilePath)
+	if err != nil {
+		return false
+	}
+	return !fi.ModTime().Equal(m.dataModTime) || fi.Size() != m.dataSize
+}
+
 func (m Model) getCurrentKey() string {
 	if m.ShowFuture {
 		return "Future"
@@ -194,6 +268,30 @@ func (m *Model) clampRow() {
 	if m.RowIdx < 0 {
 		m.RowIdx = 0
 	}
+	if m.filter != "" && !m.rowVisible(currentDate, m.RowIdx) {
+		if next, ok := m.nextVisibleRow(currentDate, m.RowIdx, 1); ok {
+			m.RowIdx = next
+		} else if prev, ok := m.nextVisibleRow(currentDate, m.RowIdx, -1); ok {
+			m.RowIdx = prev
+		}
+	}
+}
+
+// rowVisible reports whether the task at idx under key passes the filter.
+func (m Model) rowVisible(key string, idx int) bool {
+	tasks := m.Data[key]
+	return idx >= 0 && idx < len(tasks) && tasks[idx].MatchesFilter(m.filter)
+}
+
+// nextVisibleRow finds the nearest row past from, stepping by direction, that
+// passes the filter.
+func (m Model) nextVisibleRow(key string, from, direction int) (int, bool) {
+	for idx := from + direction; idx >= 0 && idx < len(m.Data[key]); idx += direction {
+		if m.rowVisible(key, idx) {
+			return idx, true
+		}
+	}
+	return from, false
 }
 
 func (m *Model) configureTextInput(placeholder string) {
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -14,6 +15,7 @@ func TestPersistReportsErrors(t *testing.T) {
 	defer os.Chmod(tmpDir, 0700)
 
 	m := Model{
+		Calendar: testCalendar(),
 		Data:     make(TodoData),
 		FilePath: filepath.Join(tmpDir, "tasks.json"),
 	}
@@ -23,3 +25,25 @@ func TestPersistReportsErrors(t *testing.T) {
 		t.Fatalf("expected persist to surface error when directory is not writable")
 	}
 }
+
+func TestPersistRunsAfterSaveAndReportsItsError(t *testing.T) {
+	m := newReloadTestModel(t)
+	m.base = make(TodoData)
+	var saved []TodoData
+	m.SaveOptions.AfterSave = func(data TodoData) error {
+		saved = append(saved, data)
+		return errors.New("feed unwritable")
+	}
+	m.Data.Add(FutureKey, NewTask("Saved anyway", testNow()))
+
+	m.persist()
+	if len(saved) != 1 {
+		t.Fatalf("expected one call after a save, got %d", len(saved))
+	}
+	if !errors.Is(m.Err, ErrAfterSave) {
+		t.Errorf("expected the hook's error surfaced, got %v", m.Err)
+	}
+	if !sameJSON(m.base, m.Data) {
+		t.Errorf("expected the saved data to become the merge base despite the hook failing")
+	}
+}
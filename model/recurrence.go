@@ -0,0 +1,211 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type recurrenceKind int
+
+const (
+	repeatDaily recurrenceKind = iota
+	repeatWeekdays
+	repeatEveryNDays
+	repeatWeekly
+	repeatMonthly
+)
+
+// Recurrence is a parsed Task.Repeat rule. Rules are stored in the data file
+// in their canonical text form so they stay readable and hand-editable:
+//
+//	daily
+//	weekdays
+//	every 3 days
+//	weekly on mon,thu
+//	monthly on 15
+type Recurrence struct {
+	kind     recurrenceKind
+	interval int
+	weekdays []time.Weekday
+	day      int
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseRecurrence parses a rule, accepting a few natural variants of each
+// canonical form ("every weekday", "weekly on monday and friday",
+// "monthly on the 1st").
+func ParseRecurrence(rule string) (Recurrence, error) {
+	fields := strings.Fields(strings.ToLower(strings.NewReplacer(",", " ").Replace(rule)))
+	invalid := fmt.Errorf("unknown repeat rule %q; use daily, weekdays, every N days, weekly on mon,fri, or monthly on N", strings.TrimSpace(rule))
+	if len(fields) == 0 {
+		return Recurrence{}, invalid
+	}
+
+	switch strings.Join(fields, " ") {
+	case "daily", "every day":
+		return Recurrence{kind: repeatDaily}, nil
+	case "weekdays", "every weekday":
+		return Recurrence{kind: repeatWeekdays}, nil
+	}
+
+	switch {
+	case fields[0] == "every" && len(fields) == 3 && (fields[2] == "days" || fields[2] == "day"):
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 1 {
+			return Recurrence{}, invalid
+		}
+		if n == 1 {
+			return Recurrence{kind: repeatDaily}, nil
+		}
+		return Recurrence{kind: repeatEveryNDays, interval: n}, nil
+
+	case fields[0] == "weekly" && len(fields) > 2 && fields[1] == "on":
+		seen := map[time.Weekday]bool{}
+		for _, name := range fields[2:] {
+			if name == "and" {
+				continue
+			}
+			if len(name) < 3 {
+				return Recurrence{}, invalid
+			}
+			day, ok := weekdayNames[name[:3]]
+			if !ok {
+				return Recurrence{}, invalid
+			}
+			seen[day] = true
+		}
+		r := Recurrence{kind: repeatWeekly}
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if seen[day] {
+				r.weekdays = append(r.weekdays, day)
+			}
+		}
+		if len(r.weekdays) == 0 {
+			return Recurrence{}, invalid
+		}
+		return r, nil
+
+	case fields[0] == "monthly" && len(fields) > 2 && fields[1] == "on":
+		dayFields := fields[2:]
+		if len(dayFields) == 2 && dayFields[0] == "the" {
+			dayFields = dayFields[1:]
+		}
+		if len(dayFields) != 1 {
+			return Recurrence{}, invalid
+		}
+		day, err := strconv.Atoi(strings.TrimRight(dayFields[0], "stndrh"))
+		if err != nil || day < 1 || day > 31 {
+			return Recurrence{}, invalid
+		}
+		return Recurrence{kind: repeatMonthly, day: day}, nil
+	}
+	return Recurrence{}, invalid
+}
+
+// String returns the canonical form stored in Task.Repeat.
+func (r Recurrence) String() string {
+	switch r.kind {
+	case repeatWeekdays:
+		return "weekdays"
+	case repeatEveryNDays:
+		return fmt.Sprintf("every %d days", r.interval)
+	case repeatWeekly:
+		names := make([]string, len(r.weekdays))
+		for i, day := range r.weekdays {
+			names[i] = strings.ToLower(day.String()[:3])
+		}
+		return "weekly on " + strings.Join(names, ",")
+	case repeatMonthly:
+		return fmt.Sprintf("monthly on %d", r.day)
+	default:
+		return "daily"
+	}
+}
+
+// Next returns the first day strictly after the given day that the rule
+// lands on. Monthly rules for days a month lacks fall on its last day.
+func (r Recurrence) Next(after time.Time) time.Time {
+	after = startOfDay(after)
+	switch r.kind {
+	case repeatEveryNDays:
+		return after.AddDate(0, 0, r.interval)
+	case repeatWeekdays, repeatWeekly:
+		for date := after.AddDate(0, 0, 1); ; date = date.AddDate(0, 0, 1) {
+			if r.matchesWeekday(date.Weekday()) {
+				return date
+			}
+		}
+	case repeatMonthly:
+		for months := 0; ; months++ {
+			first := time.Date(after.Year(), after.Month()+time.Month(months), 1, 0, 0, 0, 0, after.Location())
+			lastDay := first.AddDate(0, 1, -1).Day()
+			date := first.AddDate(0, 0, min(r.day, lastDay)-1)
+			if date.After(after) {
+				return date
+			}
+		}
+	default:
+		return after.AddDate(0, 0, 1)
+	}
+}
+
+func (r Recurrence) matchesWeekday(day time.Weekday) bool {
+	if r.kind == repeatWeekdays {
+		return day != time.Saturday && day != time.Sunday
+	}
+	for _, d := range r.weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOccurrence returns the date (YYYY-MM-DD) the task filed under key
+// should recur on once completed. Occurrences count on from the later of the
+// task's own date and today, so finishing a chore late never schedules the
+// next one in the past.
+func (t Task) NextOccurrence(key string, today time.Time) (string, bool) {
+	if t.Repeat == "" {
+		return "", false
+	}
+	rule, err := ParseRecurrence(t.Repeat)
+	if err != nil {
+		return "", false
+	}
+
+	anchor := today
+	date := t.DueDate
+	if date == "" && key != FutureKey {
+		date = key
+	}
+	if parsed, err := parseDate(date, today.Location()); err == nil && parsed.After(anchor) {
+		anchor = parsed
+	}
+	return rule.Next(anchor).Format(dateLayout), true
+}
+
+// scheduleNextOccurrence files the next occurrence of a just-completed
+// recurring task in Future with its DueDate, leaving
+// distributeFutureTasksThrough to surface it when its day is in view. The
+// rule moves to the new occurrence, so reopening and re-completing the
+// finished one cannot spawn duplicates. The occurrence is created now by cal.
+func (d TodoData) scheduleNextOccurrence(key string, idx int, cal Calendar) {
+	task := d[key][idx]
+	date, ok := task.NextOccurrence(key, cal.Today())
+	if !ok {
+		return
+	}
+	next := NewTask(task.Title, cal.Now())
+	next.Notes = task.Notes
+	next.Repeat = task.Repeat
+	next.DueDate = date
+	d[key][idx].Repeat = ""
+	d.Add(FutureKey, next)
+}
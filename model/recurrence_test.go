@@ -0,0 +1,148 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+func TestParseRecurrenceCanonicalises(t *testing.T) {
+	tests := map[string]string{
+		"daily":                       "daily",
+		"Every Day":                   "daily",
+		"every 1 day":                 "daily",
+		"every weekday":               "weekdays",
+		"every 3 days":                "every 3 days",
+		"weekly on fri, mon":          "weekly on mon,fri",
+		"weekly on Monday and Friday": "weekly on mon,fri",
+		"monthly on 15":               "monthly on 15",
+		"monthly on the 1st":          "monthly on 1",
+	}
+	for input, want := range tests {
+		rule, err := ParseRecurrence(input)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) error: %v", input, err)
+			continue
+		}
+		if got := rule.String(); got != want {
+			t.Errorf("ParseRecurrence(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", "hourly", "every 0 days", "weekly on", "weekly on funday", "monthly on 32", "monthly on the"} {
+		if _, err := ParseRecurrence(input); err == nil {
+			t.Errorf("ParseRecurrence(%q) should fail", input)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	friday := time.Date(2026, 1, 30, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		rule  string
+		after time.Time
+		want  string
+	}{
+		{"daily", friday, "2026-01-31"},
+		{"weekdays", friday, "2026-02-02"},
+		{"every 3 days", friday, "2026-02-02"},
+		{"weekly on mon,thu", friday, "2026-02-02"},
+		{"weekly on fri", friday, "2026-02-06"},
+		{"monthly on 30", friday, "2026-02-28"},
+		{"monthly on 31", friday, "2026-01-31"},
+		{"monthly on 1", friday, "2026-02-01"},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rule.Next(tt.after).Format(dateLayout); got != tt.want {
+			t.Errorf("%s after %s = %s, want %s", tt.rule, tt.after.Format(dateLayout), got, tt.want)
+		}
+	}
+}
+
+func TestCompletingRecurringTaskSchedulesNextOnce(t *testing.T) {
+	cal := testCalendar()
+	today := cal.Today().Format(dateLayout)
+	tomorrow := cal.Today().AddDate(0, 0, 1).Format(dateLayout)
+	data := TodoData{today: {{ID: "1", Title: "Standup notes", Repeat: "daily", Notes: "Template in wiki"}}}
+
+	data.Toggle(today, 0, cal)
+	future := data[FutureKey]
+	if len(future) != 1 {
+		t.Fatalf("expected the next occurrence in Future, got %v", future)
+	}
+	next := future[0]
+	if next.DueDate != tomorrow || next.Repeat != "daily" || next.Notes != "Template in wiki" || next.Completed || next.ID == "1" {
+		t.Fatalf("unexpected next occurrence %+v", next)
+	}
+	if !next.CreatedAt.Equal(cal.Now()) {
+		t.Errorf("CreatedAt = %v, want the calendar's now", next.CreatedAt)
+	}
+	if data[today][0].Repeat != "" {
+		t.Error("the completed occurrence should hand its rule on")
+	}
+
+	data.Toggle(today, 0, cal)
+	data.Toggle(today, 0, cal)
+	if len(data[FutureKey]) != 1 {
+		t.Fatalf("reopening and completing again must not duplicate, got %v", data[FutureKey])
+	}
+}
+
+func TestLateCompletionCountsFromToday(t *testing.T) {
+	today := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	task := Task{Title: "Water plants", Repeat: "every 3 days", DueDate: today.AddDate(0, 0, -5).Format(dateLayout)}
+	if got, _ := task.NextOccurrence(today.Format(dateLayout), today); got != today.AddDate(0, 0, 3).Format(dateLayout) {
+		t.Errorf("next = %s, want three days from today", got)
+	}
+
+	ahead := Task{Title: "Water plants", Repeat: "every 3 days"}
+	key := today.AddDate(0, 0, 2).Format(dateLayout)
+	if got, _ := ahead.NextOccurrence(key, today); got != today.AddDate(0, 0, 5).Format(dateLayout) {
+		t.Errorf("next = %s, want three days after the task's own day", got)
+	}
+}
+
+func TestTUISetsRepeatAndSurfacesNextOccurrence(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	tomorrow := testNow().AddDate(0, 0, 1).Format(dateLayout)
+	m := Model{
+		Calendar:    testCalendar(),
+		Data:        TodoData{today: {{ID: "1", Title: "Timesheet"}}},
+		VisibleDays: 2,
+		State:       Browsing,
+		TextInput:   textinput.New(),
+		dateKeys:    []string{today, tomorrow},
+	}
+
+	m = pressRune(m, 'r')
+	if m.State != SettingRepeat {
+		t.Fatalf("expected repeat input, got %v", m.State)
+	}
+	m.TextInput.SetValue("hourly")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m = updated.(Model); m.State != SettingRepeat || m.Err == nil {
+		t.Fatalf("expected an invalid rule to keep the input open with an error, got %v", m.State)
+	}
+	m.TextInput.SetValue("Every Day")
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if m.State != Browsing || m.Data[today][0].Repeat != "daily" {
+		t.Fatalf("expected canonical rule saved, got %+v", m.Data[today][0])
+	}
+
+	m = pressRune(m, ' ')
+	if got := m.Data[tomorrow]; len(got) != 1 || got[0].Title != "Timesheet" || got[0].Repeat != "daily" {
+		t.Fatalf("expected the next occurrence surfaced on tomorrow, got %v", got)
+	}
+
+	m = pressRune(m, 'u')
+	if len(m.Data[tomorrow]) != 0 || m.Data[today][0].Completed {
+		t.Fatalf("expected undo to remove the spawned occurrence, got %v", m.Data)
+	}
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"time"
 
@@ -18,12 +19,15 @@ const reloadInterval = 5 * time.Second
 type reloadTickMsg time.Time
 
 // dataFileCheckedMsg carries the result of a background file check. A nil
-// data with a nil err means the file was unchanged.
+// data with a nil err means the file was unchanged. The sync-conflict copies
+// beside the file are listed on every check unless copiesErr is set.
 type dataFileCheckedMsg struct {
-	data    TodoData
-	modTime time.Time
-	size    int64
-	err     error
+	data      TodoData
+	modTime   time.Time
+	size      int64
+	err       error
+	copies    []string
+	copiesErr error
 }
 
 // reloadTick schedules the next background check of the data file.
@@ -34,19 +38,21 @@ func reloadTick() tea.Cmd {
 }
 
 // checkDataFile stats the data file off the update loop and, only if it looks
-// changed since lastMod/lastSize, reads and parses it.
+// changed since lastMod/lastSize, reads and parses it. It also lists any
+// conflicted copies a sync provider has left beside it.
 func checkDataFile(path string, lastMod time.Time, lastSize int64) tea.Cmd {
 	return func() tea.Msg {
+		copies, copiesErr := ConflictedCopies(path)
 		fi, err := os.Stat(path)
 		if err != nil {
 			// Missing or unreadable (e.g. mid-sync); try again next tick.
-			return dataFileCheckedMsg{}
+			return dataFileCheckedMsg{copies: copies, copiesErr: copiesErr}
 		}
 		if fi.ModTime().Equal(lastMod) && fi.Size() == lastSize {
-			return dataFileCheckedMsg{}
+			return dataFileCheckedMsg{copies: copies, copiesErr: copiesErr}
 		}
 		data, err := loadRaw(path)
-		return dataFileCheckedMsg{data: data, modTime: fi.ModTime(), size: fi.Size(), err: err}
+		return dataFileCheckedMsg{data: data, modTime: fi.ModTime(), size: fi.Size(), err: err, copies: copies, copiesErr: copiesErr}
 	}
 }
 
@@ -60,8 +66,15 @@ func (m Model) handleReloadTick() (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleDataFileChecked(msg dataFileCheckedMsg) (tea.Model, tea.Cmd) {
+	if msg.copiesErr == nil {
+		m.conflictedCopies = msg.copies
+	}
+
 	// Unchanged, stale (we persisted while the check was in flight), or a
 	// transient read/parse failure: keep current state and check again later.
+	if errors.Is(msg.err, ErrNewerSchema) {
+		m.Err = msg.err
+	}
 	if msg.data == nil || msg.err != nil || !msg.modTime.After(m.dataModTime) {
 		return m, reloadTick()
 	}
@@ -71,14 +84,23 @@ func (m Model) handleDataFileChecked(msg dataFileCheckedMsg) (tea.Model, tea.Cmd
 
 	if !sameJSON(m.Data, msg.data) {
 		m.applyReloadedData(msg.data)
+		// The data file was not written, but the feed follows what we show.
+		if m.SaveOptions.AfterSave != nil {
+			if err := m.SaveOptions.AfterSave(m.Data); err != nil {
+				m.Err = err
+			}
+		}
+	} else if m.base != nil {
+		m.base = msg.data
 	}
 	return m, reloadTick()
 }
 
-// applyReloadedData swaps in externally-changed data while keeping the cursor
-// on the same task where possible. Rollover/prune/distribution run in memory
-// only — persisting here would bump the file's mtime and ping-pong writes
-// with the web app; changes reach disk on the user's next edit.
+// applyReloadedData merges externally-changed data with ours, against the
+// base snapshot, while keeping the cursor on the same task where possible.
+// Without a base it is swapped in wholesale. Rollover/prune/distribution run
+// in memory only — persisting here would bump the file's mtime and ping-pong
+// writes with the web app; changes reach disk on the user's next edit.
 func (m *Model) applyReloadedData(data TodoData) {
 	focusedKey := m.getCurrentKey()
 	focusedID := ""
@@ -86,11 +108,25 @@ func (m *Model) applyReloadedData(data TodoData) {
 		focusedID = tasks[m.RowIdx].ID
 	}
 
-	m.Data = data
-	m.Data.rollOverIncompleteTasks()
-	m.Data.pruneOldTasks(m.RetentionDays)
-	m.Data.distributeFutureTasksThrough(m.lastVisibleDate())
-	m.clearMoveUndo()
+	if m.base == nil {
+		m.Data = data
+	} else {
+		merged, conflicts := mergeData(m.base, m.Data, data)
+		m.Data = merged
+		m.base = data
+		m.addConflicts(conflicts)
+	}
+	today := m.Calendar.Today()
+	m.Data.rollOverIncompleteTasks(today)
+	m.Data.pruneOldTasks(m.RetentionDays, today)
+	m.Data.distributeFutureTasksThrough(m.lastVisibleDate(), today)
+	if len(m.undoStack) > 0 || len(m.redoStack) > 0 {
+		m.clearUndo()
+		m.status = "Undo history cleared after an external change"
+	}
+	if len(m.conflicts) > 0 {
+		m.status = conflictSummary(len(m.conflicts))
+	}
 
 	if focusedID != "" {
 		for i, task := range m.Data[focusedKey] {
@@ -103,10 +139,12 @@ func (m *Model) applyReloadedData(data TodoData) {
 	m.clampRow()
 }
 
-// sameJSON reports whether two data sets marshal identically. json.Marshal
+// sameJSON reports whether two values marshal identically. json.Marshal
 // sorts map keys, so this is a cheap deterministic deep-equal — the same
-// diff gate the web app uses before re-rendering.
-func sameJSON(a, b TodoData) bool {
+// diff gate the web app uses before re-rendering. It compares tasks as the
+// data file stores them: a time read back from disk has lost its monotonic
+// reading and location, and an empty label list comes back nil.
+func sameJSON(a, b any) bool {
 	aj, errA := json.Marshal(a)
 	bj, errB := json.Marshal(b)
 	if errA != nil || errB != nil {
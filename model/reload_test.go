@@ -10,6 +10,7 @@ import (
 func newReloadTestModel(t *testing.T) Model {
 	t.Helper()
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        make(TodoData),
 		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
 		VisibleDays: 3,
@@ -20,7 +21,7 @@ func newReloadTestModel(t *testing.T) Model {
 }
 
 func TestReloadAppliesExternalChangeAndFollowsCursor(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := newReloadTestModel(t)
 	m.Data[today] = []Task{
 		{ID: "a", Title: "First"},
@@ -49,7 +50,7 @@ func TestReloadAppliesExternalChangeAndFollowsCursor(t *testing.T) {
 }
 
 func TestReloadIgnoresStaleCheckResult(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := newReloadTestModel(t)
 	m.Data[today] = []Task{{ID: "a", Title: "Keep me"}}
 	m.dataModTime = time.Now()
@@ -71,18 +72,18 @@ func TestReloadIgnoresStaleCheckResult(t *testing.T) {
 }
 
 func TestReloadDiffGateKeepsModelUntouched(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := newReloadTestModel(t)
 	m.Data[today] = []Task{{ID: "a", Title: "Same"}}
-	m.moveUndo = &moveUndoSnapshot{Data: cloneTodoData(m.Data)}
+	m.recordUndo("edit")
 
 	same := TodoData{today: {{ID: "a", Title: "Same"}}}
 	newMod := time.Now()
 	newM, _ := m.Update(dataFileCheckedMsg{data: same, modTime: newMod, size: 42})
 	m = newM.(Model)
 
-	if m.moveUndo == nil {
-		t.Error("identical content should not clear the move undo snapshot")
+	if len(m.undoStack) != 1 {
+		t.Error("identical content should not clear undo history")
 	}
 	if !m.dataModTime.Equal(newMod) || m.dataSize != 42 {
 		t.Error("tracked file state should still advance on an mtime-only change")
@@ -105,11 +106,11 @@ func TestReloadTickSkippedWhileTyping(t *testing.T) {
 }
 
 func TestCheckDataFileEndToEnd(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := newReloadTestModel(t)
 
 	external := TodoData{today: {{ID: "w", Title: "From the web"}}}
-	if err := external.Save(m.FilePath); err != nil {
+	if err := external.Save(m.FilePath, SaveOptions{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -140,7 +141,7 @@ func TestCheckDataFileEndToEnd(t *testing.T) {
 }
 
 func TestPersistPreventsSelfDetection(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := newReloadTestModel(t)
 	m.Data[today] = []Task{{ID: "a", Title: "Mine"}}
 
@@ -157,8 +158,8 @@ func TestPersistPreventsSelfDetection(t *testing.T) {
 }
 
 func TestReloadRollsOverInMemoryWithoutSaving(t *testing.T) {
-	today := time.Now().Format(dateLayout)
-	yesterday := time.Now().AddDate(0, 0, -1).Format(dateLayout)
+	today := testNow().Format(dateLayout)
+	yesterday := testNow().AddDate(0, 0, -1).Format(dateLayout)
 	m := newReloadTestModel(t)
 
 	external := TodoData{yesterday: {{ID: "r", Title: "Left behind", Completed: false}}}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SchemaVersion is the data file format this build reads and writes. The
+// file is an envelope, {"version": N, "tasks": {...}}; files written before
+// versioning are a bare map of tasks and read as version 0.
+const SchemaVersion = 1
+
+// ErrNewerSchema means the data file was written in a format newer than this
+// build understands. Such a file is never written, so an old binary cannot
+// clobber fields it does not know about.
+var ErrNewerSchema = errors.New("data file was written by a newer version of doitdoit")
+
+// dataFile is the versioned envelope stored on disk.
+type dataFile struct {
+	Version int      `json:"version"`
+	Tasks   TodoData `json:"tasks"`
+}
+
+// document is a data file decoded just far enough for migrations to
+// restructure it.
+type document map[string]json.RawMessage
+
+// migration upgrades a document from version From to From+1.
+type migration struct {
+	From        int
+	Description string
+	Apply       func(document) (document, error)
+}
+
+// migrations is the ordered upgrade path: entry i upgrades version i, so the
+// last entry ends at SchemaVersion. Append a migration and bump
+// SchemaVersion together; never edit one that has shipped.
+var migrations = []migration{
+	{
+		From:        0,
+		Description: "wrap the bare task map in a versioned envelope",
+		Apply: func(doc document) (document, error) {
+			tasks, err := json.Marshal(doc)
+			if err != nil {
+				return nil, err
+			}
+			return document{"tasks": tasks}, nil
+		},
+	},
+}
+
+// decodeDataFile reads data file contents in any supported version,
+// migrating older ones in memory. Empty contents are an empty task list.
+func decodeDataFile(raw []byte) (TodoData, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return make(TodoData), nil
+	}
+	var doc document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, err
+	}
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("%w (schema version %d, this build supports %d); upgrade doitdoit to open it", ErrNewerSchema, version, SchemaVersion)
+	}
+	if doc, err = migrate(doc, version, migrations); err != nil {
+		return nil, err
+	}
+
+	var file dataFile
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(encoded, &file); err != nil {
+		return nil, err
+	}
+	if file.Tasks == nil {
+		file.Tasks = make(TodoData)
+	}
+	return file.Tasks, nil
+}
+
+// documentVersion reads a document's version. Only an envelope has a numeric
+// "version"; in a bare task map every value is a list.
+func documentVersion(doc document) (int, error) {
+	raw, ok := doc["version"]
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("invalid schema version %s", raw)
+	}
+	return version, nil
+}
+
+// migrate applies every migration from version onwards, stamping each step's
+// version as it goes.
+func migrate(doc document, version int, registry []migration) (document, error) {
+	for _, step := range registry {
+		if step.From < version {
+			continue
+		}
+		if step.From != version {
+			return nil, fmt.Errorf("no migration from schema version %d", version)
+		}
+		next, err := step.Apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("migrating schema version %d: %w", version, err)
+		}
+		version++
+		stamp, _ := json.Marshal(version)
+		next["version"] = stamp
+		doc = next
+	}
+	return doc, nil
+}
+
+// encodeDataFile renders tasks as the current envelope.
+func encodeDataFile(d TodoData) ([]byte, error) {
+	return json.MarshalIndent(dataFile{Version: SchemaVersion, Tasks: d}, "", "  ")
+}
+
+// refuseNewerSchema fails when the file at path is in a newer format, so Save
+// never downgrades it. A missing or unparseable file is left to the caller.
+func refuseNewerSchema(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var doc document
+	if json.Unmarshal(raw, &doc) != nil {
+		return nil
+	}
+	if version, err := documentVersion(doc); err == nil && version > SchemaVersion {
+		return fmt.Errorf("%w (schema version %d); refusing to overwrite it", ErrNewerSchema, version)
+	}
+	return nil
+}
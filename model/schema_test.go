@@ -0,0 +1,95 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrationRegistryIsOrdered(t *testing.T) {
+	if len(migrations) != SchemaVersion {
+		t.Fatalf("%d migrations for schema version %d", len(migrations), SchemaVersion)
+	}
+	for i, step := range migrations {
+		if step.From != i || step.Description == "" || step.Apply == nil {
+			t.Errorf("migration %d is %+v, want From=%d with a description", i, step, i)
+		}
+	}
+}
+
+func TestLoadReadsLegacyBareMapAndSavesEnvelope(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	legacy := `{"` + today + `":[{"id":"1","title":"Legacy"}],"Future":[]}`
+	if err := os.WriteFile(path, []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Load(path, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data[today]) != 1 || data[today][0].Title != "Legacy" {
+		t.Fatalf("legacy tasks not read, got %v", data)
+	}
+	if err := data.Save(path, SaveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := os.ReadFile(path)
+	var file struct {
+		Version int                        `json:"version"`
+		Tasks   map[string]json.RawMessage `json:"tasks"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		t.Fatal(err)
+	}
+	if file.Version != SchemaVersion || file.Tasks[today] == nil {
+		t.Errorf("expected a version %d envelope, got %s", SchemaVersion, raw)
+	}
+}
+
+func TestMigrateRunsStepsInOrder(t *testing.T) {
+	var ran []int
+	step := func(from int) migration {
+		return migration{From: from, Description: "test", Apply: func(doc document) (document, error) {
+			ran = append(ran, from)
+			doc["seen"] = json.RawMessage(`true`)
+			return doc, nil
+		}}
+	}
+	registry := []migration{step(0), step(1), step(2)}
+
+	doc, err := migrate(document{"version": json.RawMessage(`1`)}, 1, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 2 || ran[0] != 1 || ran[1] != 2 || string(doc["version"]) != "3" {
+		t.Errorf("ran %v ending at version %s, want [1 2] ending at 3", ran, doc["version"])
+	}
+
+	if _, err := migrate(document{}, 0, []migration{step(1)}); err == nil {
+		t.Error("expected a gap in the registry to fail")
+	}
+}
+
+func TestNewerSchemaIsRefusedAndNeverOverwritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	newer := `{"version":99,"tasks":{},"archive":{}}`
+	if err := os.WriteFile(path, []byte(newer), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path, 0, testCalendar().Today(), SaveOptions{}); !errors.Is(err, ErrNewerSchema) || !strings.Contains(err.Error(), "upgrade") {
+		t.Fatalf("expected a newer-schema error, got %v", err)
+	}
+	if err := (TodoData{}).Save(path, SaveOptions{}); !errors.Is(err, ErrNewerSchema) {
+		t.Fatalf("expected Save to refuse, got %v", err)
+	}
+	if raw, _ := os.ReadFile(path); string(raw) != newer {
+		t.Errorf("newer file was modified: %s", raw)
+	}
+}
@@ -18,6 +18,7 @@ func TestRightNavigationScrollsViewportAndLoadsDueTasks(t *testing.T) {
 	today := dayKey(0)
 	dueDate := dayKey(3)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{"Future": {{ID: "due", Title: "Due later", DueDate: dueDate}}},
 		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
 		VisibleDays: 3,
@@ -48,7 +49,7 @@ func TestRightNavigationScrollsViewportAndLoadsDueTasks(t *testing.T) {
 }
 
 func TestLeftNavigationScrollsBackButStopsAtToday(t *testing.T) {
-	m := Model{Data: TodoData{}, VisibleDays: 3, State: Browsing, todayKey: dayKey(0)}
+	m := Model{Calendar: testCalendar(), Data: TodoData{}, VisibleDays: 3, State: Browsing, todayKey: dayKey(0)}
 	m.updateDateKeysFrom(startOfDayNow().AddDate(0, 0, 2))
 	m.ColIdx = 0
 
@@ -68,6 +69,7 @@ func TestLeftNavigationScrollsBackButStopsAtToday(t *testing.T) {
 
 func TestScrollingDoesNotMoveUndatedFutureTasks(t *testing.T) {
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{"Future": {{ID: "someday", Title: "Someday"}}},
 		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
 		VisibleDays: 1,
@@ -85,5 +87,5 @@ func TestScrollingDoesNotMoveUndatedFutureTasks(t *testing.T) {
 }
 
 func startOfDayNow() time.Time {
-	return startOfDay(time.Now())
+	return testCalendar().Today()
 }
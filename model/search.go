@@ -0,0 +1,218 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/dtt101/doitdoit/styles"
+)
+
+// maxSearchRows is how many results the search modal lists at once.
+const maxSearchRows = 10
+
+// searchResult is one title match and where it is filed.
+type searchResult struct {
+	Key  string
+	Task Task
+}
+
+// searchTasks matches query against every title in the data, case
+// insensitively. Results run from today onwards, then Future, then past
+// history most recent first, so upcoming work outranks old completions.
+func searchTasks(data TodoData, query string, today time.Time) []searchResult {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	var results []searchResult
+	for key, tasks := range data {
+		for _, task := range tasks {
+			if strings.Contains(strings.ToLower(task.Title), query) {
+				results = append(results, searchResult{Key: key, Task: task})
+			}
+		}
+	}
+
+	todayKey := today.Format(dateLayout)
+	rank := func(r searchResult) int {
+		switch {
+		case r.Key == FutureKey:
+			return 1
+		case r.Key < todayKey:
+			return 2
+		default:
+			return 0
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		switch rank(a) {
+		case 1:
+			return a.Task.DueDate != "" && (b.Task.DueDate == "" || a.Task.DueDate < b.Task.DueDate)
+		case 2:
+			return a.Key > b.Key
+		default:
+			return a.Key < b.Key
+		}
+	})
+	return results
+}
+
+func (m *Model) openSearch() {
+	m.State = Searching
+	m.configureTextInput("Search all tasks...")
+	m.searchResults = nil
+	m.searchIdx = 0
+}
+
+func (m Model) handleSearchingKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.TextInput.Reset()
+		m.searchResults = nil
+		m.State = Browsing
+	case "enter":
+		if len(m.searchResults) == 0 {
+			return m, nil
+		}
+		result := m.searchResults[m.searchIdx]
+		m.TextInput.Reset()
+		m.searchResults = nil
+		m.State = Browsing
+		if m.jumpTo(result) {
+			m.persist()
+		}
+	case "up", "ctrl+p":
+		if m.searchIdx > 0 {
+			m.searchIdx--
+		}
+	case "down", "ctrl+n":
+		if m.searchIdx < len(m.searchResults)-1 {
+			m.searchIdx++
+		}
+	default:
+		var cmd tea.Cmd
+		m.TextInput, cmd = m.TextInput.Update(msg)
+		m.searchResults = searchTasks(m.Data, m.TextInput.Value(), m.Calendar.Today())
+		m.searchIdx = 0
+		return m, cmd
+	}
+	return m, nil
+}
+
+// jumpTo focuses a search result. Dated tasks bring their day into view with
+// updateDateKeysFrom, including dated Future tasks, which are then surfaced
+// onto that day, and past days open in history. It reports whether that
+// surfacing changed the data.
+func (m *Model) jumpTo(result searchResult) bool {
+	date := result.Key
+	if date == FutureKey {
+		date = result.Task.DueDate
+	}
+	if !result.Task.MatchesFilter(m.filter) {
+		m.filter = ""
+	}
+
+	today := m.Calendar.Today()
+	changed := false
+	if date != "" && date < today.Format(dateLayout) {
+		target, err := parseDate(date, today.Location())
+		if err != nil {
+			return false
+		}
+		m.enterHistory(target)
+		m.focusDate(date)
+	} else if date == "" {
+		m.history = false
+		m.ShowFuture = true
+	} else {
+		m.ShowFuture = false
+		target, err := parseDate(date, today.Location())
+		if err != nil {
+			return false
+		}
+		if m.history || target.Before(m.firstVisibleDate()) || target.After(m.lastVisibleDate()) {
+			m.history = false
+			m.updateDateKeysFrom(target)
+		}
+		changed = m.Data.distributeFutureTasksThrough(m.lastVisibleDate(), today)
+		m.focusDate(date)
+	}
+
+	if key, idx, ok := m.Data.locate(result.Task.ID); ok && key == m.getCurrentKey() {
+		m.RowIdx = idx
+	}
+	m.clampRow()
+	return changed
+}
+
+// focusDate moves the cursor to date's column when it is in view.
+func (m *Model) focusDate(date string) {
+	for i, key := range m.dateKeys {
+		if key == date {
+			m.ColIdx = i
+		}
+	}
+}
+
+// describeResultPlace labels where a search result lives.
+func describeResultPlace(result searchResult, today time.Time) string {
+	if result.Key == FutureKey {
+		if result.Task.DueDate != "" {
+			return "Future · " + result.Task.DueDate
+		}
+		return "Future"
+	}
+	return describeKey(result.Key, today)
+}
+
+func (m Model) searchModalView() string {
+	frame := m.modal(72, styles.Highlight)
+	innerWidth := frame.innerWidth()
+
+	subtle := lipgloss.NewStyle().Foreground(styles.Subtle)
+	lines := []string{
+		"/ " + m.TextInput.View(),
+		"",
+	}
+
+	switch {
+	case strings.TrimSpace(m.TextInput.Value()) == "":
+		lines = append(lines, subtle.Render("Type to search every day, Future, and history."))
+	case len(m.searchResults) == 0:
+		lines = append(lines, subtle.Render("No matches."))
+	default:
+		// Keep the selection in view as it moves past the visible rows.
+		start := max(0, m.searchIdx-maxSearchRows+1)
+		end := min(len(m.searchResults), start+maxSearchRows)
+		placeWidth := min(24, innerWidth/3)
+		today := m.Calendar.Today()
+		for i := start; i < end; i++ {
+			result := m.searchResults[i]
+			place := subtle.Width(placeWidth).MaxWidth(placeWidth).Render(describeResultPlace(result, today))
+			titleStyle := lipgloss.NewStyle().Foreground(styles.Text)
+			if result.Task.Completed {
+				titleStyle = styles.CompletedTaskStyle
+			}
+			if i == m.searchIdx {
+				titleStyle = titleStyle.Foreground(styles.Highlight).Bold(true)
+			}
+			title := titleStyle.MaxWidth(max(1, innerWidth-placeWidth-1)).Render(result.Task.Title)
+			lines = append(lines, place+" "+title)
+		}
+		if more := len(m.searchResults) - end; more > 0 {
+			lines = append(lines, subtle.Render(fmt.Sprintf("…and %d more", more)))
+		}
+	}
+
+	lines = append(lines, "", subtle.Render("↑/↓ choose · enter jump · esc close"))
+	return frame.renderModal("Search", lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
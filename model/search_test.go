@@ -0,0 +1,132 @@
+package model
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+func newSearchTestModel(t *testing.T) Model {
+	t.Helper()
+	day := func(offset int) string { return testNow().AddDate(0, 0, offset).Format(dateLayout) }
+	m := Model{
+		Calendar: testCalendar(),
+		Data: TodoData{
+			day(-10): {{ID: "old", Title: "Renew insurance", Completed: true}},
+			day(-2):  {{ID: "recent", Title: "Renew library books", Completed: true}},
+			day(0):   {{ID: "today", Title: "Pay rent"}, {ID: "today2", Title: "Renew gym pass"}},
+			FutureKey: {
+				{ID: "undated", Title: "Renew passport someday"},
+				{ID: "later", Title: "Renew car tax", DueDate: day(35)},
+			},
+		},
+		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
+		VisibleDays: 3,
+		State:       Browsing,
+		TextInput:   textinput.New(),
+		width:       100,
+		height:      40,
+	}
+	m.updateDateKeys()
+	return m
+}
+
+func typeSearch(m Model, query string) Model {
+	m = pressRune(m, '/')
+	for _, r := range query {
+		m = pressRune(m, r)
+	}
+	return m
+}
+
+func TestSearchRanksUpcomingThenFutureThenHistory(t *testing.T) {
+	m := typeSearch(newSearchTestModel(t), "RENEW")
+	if m.State != Searching {
+		t.Fatalf("expected search mode, got %v", m.State)
+	}
+
+	var ids []string
+	for _, result := range m.searchResults {
+		ids = append(ids, result.Task.ID)
+	}
+	if got := strings.Join(ids, ","); got != "today2,later,undated,recent,old" {
+		t.Fatalf("result order = %s", got)
+	}
+
+	view := m.View().Content
+	for _, want := range []string{"Renew car tax", "Future · " + testNow().AddDate(0, 0, 35).Format(dateLayout), "Today"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the search modal, got %q", want, view)
+		}
+	}
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	m = typeSearch(updated.(Model), "")
+	if m.State != Searching || m.TextInput.Value() != "" || len(m.searchResults) != 0 {
+		t.Errorf("expected reopening search to start empty, got %q with %d results", m.TextInput.Value(), len(m.searchResults))
+	}
+}
+
+func TestSearchJumpsViewportToDatedFutureTask(t *testing.T) {
+	target := testNow().AddDate(0, 0, 35).Format(dateLayout)
+	m := typeSearch(newSearchTestModel(t), "car tax")
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.State != Browsing || m.ShowFuture {
+		t.Fatalf("expected to land in the day view, got state=%v future=%v", m.State, m.ShowFuture)
+	}
+	if m.dateKeys[0] != target || m.ColIdx != 0 {
+		t.Fatalf("expected the window to start on %s, got %v col=%d", target, m.dateKeys, m.ColIdx)
+	}
+	if task, ok := m.selectedTask(); !ok || task.ID != "later" {
+		t.Fatalf("expected the cursor on the surfaced task, got %+v", task)
+	}
+
+	loaded, err := Load(m.FilePath, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded[target]) != 1 {
+		t.Errorf("expected the surfaced task to be saved under its day, got %v", loaded)
+	}
+}
+
+func TestSearchJumpsToUndatedFutureAndClearsHidingFilter(t *testing.T) {
+	m := newSearchTestModel(t)
+	m.filter = "#work"
+	m = typeSearch(m, "passport")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+
+	if !m.ShowFuture || m.filter != "" {
+		t.Fatalf("expected Future view without the filter, got future=%v filter=%q", m.ShowFuture, m.filter)
+	}
+	if task, ok := m.selectedTask(); !ok || task.ID != "undated" {
+		t.Fatalf("expected the cursor on the undated task, got %+v", task)
+	}
+}
+
+func TestSearchNavigatesResultsIntoHistory(t *testing.T) {
+	m := typeSearch(newSearchTestModel(t), "renew")
+	for range 4 {
+		updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		m = updated.(Model)
+	}
+	if m.searchIdx != 4 {
+		t.Fatalf("expected the last result selected, got %d", m.searchIdx)
+	}
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.State != Browsing || !m.history {
+		t.Fatalf("expected a past result to open history, got state=%v history=%v", m.State, m.history)
+	}
+	if task, ok := m.selectedTask(); !ok || task.ID != "old" {
+		t.Fatalf("expected the cursor on the past task, got %+v in %v", task, m.dateKeys)
+	}
+}
@@ -0,0 +1,12 @@
+package model
+
+// A task that rollover keeps carrying forward is one nobody has decided
+// about. Once it has rolled more than the staleness threshold, the TUI marks
+// it and `doitdoit stale` lists it, so it gets finished, rescheduled, or
+// dropped instead of drifting forever.
+
+// RolledOverMoreThan reports whether the task is open and has rolled over
+// more than the given number of times.
+func (t Task) RolledOverMoreThan(rollovers int) bool {
+	return !t.Completed && t.Rollovers > rollovers
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRolloverCountsEventsAndKeepsFirstScheduled(t *testing.T) {
+	today := time.Date(2026, 10, 14, 0, 0, 0, 0, time.Local)
+	threeDaysAgo := "2026-10-11"
+	data := TodoData{threeDaysAgo: {
+		{ID: "1", Title: "Call the bank"},
+		{ID: "2", Title: "Sent invoice", Completed: true},
+	}}
+
+	data.rollOverIncompleteTasks(today)
+	task := data["2026-10-14"][0]
+	// Three days skipped in one rollover still count once.
+	if task.Rollovers != 1 || task.FirstScheduled != threeDaysAgo {
+		t.Fatalf("expected 1 rollover since %s, got %+v", threeDaysAgo, task)
+	}
+	if done := data[threeDaysAgo][0]; done.Rollovers != 0 || done.FirstScheduled != "" {
+		t.Errorf("completed tasks should not be counted, got %+v", done)
+	}
+
+	// A day later the count grows, but the original plan is kept.
+	data.rollOverIncompleteTasks(today.AddDate(0, 0, 1))
+	task = data["2026-10-15"][0]
+	if task.Rollovers != 2 || task.FirstScheduled != threeDaysAgo {
+		t.Fatalf("expected 2 rollovers since %s, got %+v", threeDaysAgo, task)
+	}
+}
+
+func TestRescheduleResetsRolloversButKeepsFirstScheduled(t *testing.T) {
+	today := calendarAt(time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local)).Today()
+	data := TodoData{"2026-10-14": {{ID: "1", Title: "Call the bank", Rollovers: 6, FirstScheduled: "2026-10-08"}}}
+
+	key, moved, err := data.Reschedule("2026-10-14", 0, "2026-10-15", today, today.AddDate(0, 0, 2))
+	if err != nil || !moved {
+		t.Fatalf("moved=%v err=%v", moved, err)
+	}
+	if task := data[key][0]; task.Rollovers != 0 || task.FirstScheduled != "2026-10-08" {
+		t.Errorf("expected the count reset and the first date kept, got %+v", task)
+	}
+}
+
+// staleAfter matches the threshold a config that does not set one uses.
+const staleAfter = 3
+
+func TestRolledOverMoreThan(t *testing.T) {
+	task := Task{Title: "Call the bank", Rollovers: 4}
+	if !task.RolledOverMoreThan(staleAfter) {
+		t.Errorf("4 rollovers should be stale at a threshold of %d", staleAfter)
+	}
+	if (Task{Rollovers: staleAfter}).RolledOverMoreThan(staleAfter) {
+		t.Errorf("exactly %d rollovers should not be stale yet", staleAfter)
+	}
+	if (Task{Rollovers: 9, Completed: true}).RolledOverMoreThan(staleAfter) {
+		t.Error("completed tasks are never stale")
+	}
+}
+
+func TestRenderTaskBadgesMarksStaleTasks(t *testing.T) {
+	m := Model{Calendar: calendarAt(time.Date(2026, 10, 14, 9, 0, 0, 0, time.Local)), StaleAfter: staleAfter}
+	if got := m.renderTaskBadges(Task{Title: "Fresh", Rollovers: 1}); got != "" {
+		t.Errorf("expected no badge for a fresh task, got %q", got)
+	}
+	stale := Task{Title: "Call the bank", Rollovers: 7, Deadline: "2026-10-16"}
+	got := m.renderTaskBadges(stale)
+	if !strings.Contains(got, "due in 2d") || !strings.Contains(got, "rolled 7×") {
+		t.Errorf("expected deadline and staleness badges, got %q", got)
+	}
+
+	m.StaleAfter = 0
+	if got := m.renderTaskBadges(stale); strings.Contains(got, "rolled") {
+		t.Errorf("a zero threshold should turn staleness off, got %q", got)
+	}
+}
@@ -1,25 +1,44 @@
 package model
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"time"
+
+	"github.com/dtt101/doitdoit/lockfile"
 )
 
 const dateLayout = "2006-01-02"
 
+// FutureKey is the TodoData key holding undated tasks and dated tasks whose
+// day has not yet scrolled into view.
+const FutureKey = "Future"
+
 // startOfDay returns t truncated to midnight in its own location, so dates can
 // be compared without the current clock time skewing the result.
 func startOfDay(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
 }
 
-// parseDate parses a YYYY-MM-DD key as a local calendar day (midnight local),
-// so it lines up with startOfDay(time.Now()) rather than a UTC midnight.
-func parseDate(s string) (time.Time, error) {
-	return time.ParseInLocation(dateLayout, s, time.Local)
+// daysBetween counts the calendar days from one date to another, negative
+// when to is earlier. It compares dates in UTC, where every day is 24 hours
+// long, so DST changes cannot skew the count.
+func daysBetween(from, to time.Time) int {
+	y, m, d := from.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	y, m, d = to.Date()
+	end := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start) / (24 * time.Hour))
+}
+
+// parseDate parses a YYYY-MM-DD key as a calendar day (midnight in loc, the
+// location of the day Calendar.Today returns), so the two line up rather
+// than one being a UTC midnight.
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation(dateLayout, s, loc)
 }
 
 type Task struct {
@@ -28,11 +47,165 @@ type Task struct {
 	Completed bool      `json:"completed"`
 	CreatedAt time.Time `json:"created_at"`
 	DueDate   string    `json:"due_date,omitempty"`
+	// Notes is free-form, possibly multi-line text shown in the detail pane.
+	Notes string `json:"notes,omitempty"`
+	// Repeat is a recurrence rule in ParseRecurrence's canonical form.
+	Repeat string `json:"repeat,omitempty"`
+	// Deadline is the YYYY-MM-DD day the task must be done by. Unlike
+	// DueDate, rollover and moves never change it.
+	Deadline string `json:"deadline,omitempty"`
+	// Rollovers counts how many times rollover has carried the task forward
+	// since it was last scheduled on purpose, however many days each
+	// skipped. FirstScheduled is the day it was planned for when it first
+	// rolled, and survives later moves.
+	Rollovers      int    `json:"rollovers,omitempty"`
+	FirstScheduled string `json:"first_scheduled,omitempty"`
+	// Tags and Projects mirror the title's #tag and +project tokens.
+	Tags     []string `json:"tags,omitempty"`
+	Projects []string `json:"projects,omitempty"`
+	// UID is the calendar UID of a task imported from iCalendar, so a later
+	// import of the same calendar updates the task instead of copying it.
+	UID string `json:"uid,omitempty"`
+}
+
+// NewTask returns an incomplete task with a fresh ID, created at now, as a
+// Calendar reads it. IDs come from the wall clock instead, so they stay
+// unique under a fixed Clock.
+func NewTask(title string, now time.Time) Task {
+	task := Task{
+		ID:    newTaskID(),
+		Title: title,
+		// Drop any monotonic reading so the task compares equal once it
+		// has been saved and read back.
+		CreatedAt: now.Round(0),
+	}
+	task.SyncLabels()
+	return task
+}
+
+// newTaskID returns an ID from the wall clock's nanoseconds.
+func newTaskID() string {
+	return fmt.Sprintf("%d", time.Now().UnixNano())
 }
 
 // TodoData maps a date string (YYYY-MM-DD) to a list of tasks
 type TodoData map[string][]Task
 
+// Add files task under key above the first completed task, so finished work
+// always sinks to the bottom of a day. It returns the task's new index.
+func (d TodoData) Add(key string, task Task) int {
+	tasks := d[key]
+	insertIdx := len(tasks)
+	for i, t := range tasks {
+		if t.Completed {
+			insertIdx = i
+			break
+		}
+	}
+
+	if insertIdx == len(tasks) {
+		d[key] = append(tasks, task)
+	} else {
+		d[key] = insertAt(tasks, insertIdx, task)
+	}
+	return insertIdx
+}
+
+// Toggle flips the completion of the task at idx under key and re-files it:
+// a completed task sinks to the bottom and a reopened one rises above the
+// first completed task. Completing a recurring task also schedules its next
+// occurrence, no earlier than cal's today. It returns the task's new index.
+func (d TodoData) Toggle(key string, idx int, cal Calendar) (int, bool) {
+	tasks := d[key]
+	if idx < 0 || idx >= len(tasks) {
+		return 0, false
+	}
+
+	task := tasks[idx]
+	task.Completed = !task.Completed
+	tasks = append(tasks[:idx], tasks[idx+1:]...)
+	d[key] = tasks
+	if task.Completed {
+		d[key] = append(tasks, task)
+		d.scheduleNextOccurrence(key, len(tasks), cal)
+		return len(tasks), true
+	}
+	return d.Add(key, task), true
+}
+
+// Remove deletes the task at idx under key and returns it.
+func (d TodoData) Remove(key string, idx int) (Task, bool) {
+	tasks := d[key]
+	if idx < 0 || idx >= len(tasks) {
+		return Task{}, false
+	}
+	task := tasks[idx]
+	d[key] = append(tasks[:idx], tasks[idx+1:]...)
+	return task, true
+}
+
+// Reschedule moves the task at idx under key to date (YYYY-MM-DD), or to
+// undated Future when date is empty, following ScheduleKey's placement
+// rules relative to today. It returns the task's new key, and false when the
+// task was already filed there or could not be found.
+func (d TodoData) Reschedule(key string, idx int, date string, today, lastVisible time.Time) (string, bool, error) {
+	tasks := d[key]
+	if idx < 0 || idx >= len(tasks) {
+		return key, false, nil
+	}
+
+	targetKey := FutureKey
+	dueDate := ""
+	if date != "" {
+		var err error
+		targetKey, dueDate, err = ScheduleKey(date, today, lastVisible)
+		if err != nil {
+			return key, false, err
+		}
+	}
+	if key == targetKey && tasks[idx].DueDate == dueDate {
+		return key, false, nil
+	}
+	d.relocate(key, idx, targetKey, dueDate)
+	return targetKey, true, nil
+}
+
+// relocate files the task at idx under sourceKey into targetKey with the
+// given DueDate, above the target's completed tasks. A task that stays in
+// the same list keeps its position. Moving a task is a decision about it, so
+// its rollover count starts again.
+func (d TodoData) relocate(sourceKey string, idx int, targetKey, dueDate string) {
+	if sourceKey == targetKey {
+		d[sourceKey][idx].DueDate = dueDate
+		d[sourceKey][idx].Rollovers = 0
+		return
+	}
+	task, _ := d.Remove(sourceKey, idx)
+	task.DueDate = dueDate
+	task.Rollovers = 0
+	d.Add(targetKey, task)
+}
+
+// ScheduleKey returns where a task scheduled for the YYYY-MM-DD date belongs
+// and the DueDate it should carry. Past dates are clamped to today; dates up
+// to lastVisible get their own key, and later ones wait in Future until
+// distributeFutureTasksThrough surfaces them. Dates are read in today's
+// location.
+func ScheduleKey(date string, today, lastVisible time.Time) (key, dueDate string, err error) {
+	parsed, err := parseDate(date, today.Location())
+	if err != nil {
+		return "", "", err
+	}
+	if parsed.Before(today) {
+		parsed = today
+	}
+	dueDate = parsed.Format(dateLayout)
+	if parsed.After(lastVisible) {
+		return FutureKey, dueDate, nil
+	}
+	return dueDate, dueDate, nil
+}
+
 // loadRaw reads and parses the JSON file without any side effects. A missing
 // file yields an empty map.
 func loadRaw(path string) (TodoData, error) {
@@ -44,45 +217,92 @@ func loadRaw(path string) (TodoData, error) {
 			return nil, err
 		}
 
-		if err := json.Unmarshal(bytes, &data); err != nil {
+		if data, err = decodeDataFile(bytes); err != nil {
 			return nil, err
 		}
 	}
 
+	// Titles are the source of truth for labels; other writers (older
+	// versions, hand edits) may not have kept the parsed fields in step.
+	for _, tasks := range data {
+		for i := range tasks {
+			tasks[i].SyncLabels()
+		}
+	}
+
 	return data, nil
 }
 
-func Load(path string, retentionDays int) (TodoData, error) {
-	data, err := loadRaw(path)
+// Load reads the data file, rolling over incomplete tasks onto today and
+// pruning completed history older than retentionDays, and saves any such
+// change as opts asks. It holds the file's lock throughout so it cannot
+// interleave with another writer's load-change-save.
+func Load(path string, retentionDays int, today time.Time, opts SaveOptions) (TodoData, error) {
+	unlock, err := lockDataFile(path, lockfile.DefaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return load(path, retentionDays, today, opts)
+}
+
+// load is Load for callers already holding the lock.
+func load(path string, retentionDays int, today time.Time, opts SaveOptions) (TodoData, error) {
+	data, dirty, err := loadTidied(path, retentionDays, today)
 	if err != nil {
 		return nil, err
 	}
+
+	// Persist any changes triggered during load so the file stays up to date
+	if dirty {
+		if err := data.Save(path, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// loadTidied reads the data file and rolls it over and prunes it as load
+// does as of today, in memory only, reporting whether that changed anything.
+func loadTidied(path string, retentionDays int, today time.Time) (TodoData, bool, error) {
+	data, err := loadRaw(path)
+	if err != nil {
+		return nil, false, err
+	}
 	dirty := false
 
 	// Roll over incomplete tasks
-	if data.rollOverIncompleteTasks() {
+	if data.rollOverIncompleteTasks(today) {
 		dirty = true
 	}
 
 	// Prune only after a positive retention period has been explicitly passed.
-	if retentionDays > 0 && data.pruneOldTasks(retentionDays) {
+	if retentionDays > 0 && data.pruneOldTasks(retentionDays, today) {
 		dirty = true
 	}
 
-	// Persist any changes triggered during load so the file stays up to date
-	if dirty {
-		if err := data.Save(path); err != nil {
-			return nil, err
-		}
-	}
+	return data, dirty, nil
+}
 
+// Snapshot loads the data file as the TUI would show it for a viewport of
+// visibleDays starting today: incomplete tasks are rolled over and dated
+// Future tasks distributed, but only in memory. The file is never written,
+// so read-only commands cannot race an editor.
+func Snapshot(path string, visibleDays int, today time.Time) (TodoData, error) {
+	data, err := loadRaw(path)
+	if err != nil {
+		return nil, err
+	}
+	data.rollOverIncompleteTasks(today)
+	data.DistributeFutureTasks(visibleDays, today)
 	return data, nil
 }
 
-func (d TodoData) rollOverIncompleteTasks() bool {
-	now := time.Now()
-	todayStr := now.Format(dateLayout)
-	normalizedNow := startOfDay(now)
+// rollOverIncompleteTasks moves incomplete tasks from days before today onto
+// it. today is midnight of the current day, as Calendar.Today returns.
+func (d TodoData) rollOverIncompleteTasks(today time.Time) bool {
+	todayStr := today.Format(dateLayout)
 	tasksToRollOver := make([]Task, 0)
 	datesToRemove := make([]string, 0)
 	changed := false
@@ -92,16 +312,20 @@ func (d TodoData) rollOverIncompleteTasks() bool {
 			continue
 		}
 
-		parsedDate, err := parseDate(dateStr)
+		parsedDate, err := parseDate(dateStr, today.Location())
 		if err != nil {
 			continue // Skip invalid date strings
 		}
 
-		if parsedDate.Before(normalizedNow) {
+		if parsedDate.Before(today) {
 			remainingTasks := make([]Task, 0, len(tasks))
 			for _, task := range tasks {
 				if !task.Completed {
 					task.DueDate = todayStr // Update due date to today
+					if task.FirstScheduled == "" {
+						task.FirstScheduled = dateStr
+					}
+					task.Rollovers++
 					tasksToRollOver = append(tasksToRollOver, task)
 				} else {
 					remainingTasks = append(remainingTasks, task)
@@ -141,8 +365,27 @@ func (d TodoData) rollOverIncompleteTasks() bool {
 	return changed
 }
 
-func (d TodoData) Save(path string) error {
-	bytes, err := json.MarshalIndent(d, "", "  ")
+// SaveOptions are the user's settings that every save of the data file
+// follows. The zero value keeps no backups.
+type SaveOptions struct {
+	Backups BackupPolicy
+	// AfterSave, when set, runs with the saved data after every successful
+	// save, such as to rewrite a calendar feed.
+	AfterSave func(TodoData) error
+}
+
+// ErrAfterSave marks an error returned by SaveOptions.AfterSave: the data
+// file itself was saved.
+var ErrAfterSave = errors.New("tasks saved")
+
+// Save writes the data atomically in the current schema version. It refuses
+// to replace a file written in a newer version, and keeps a copy of the file
+// it replaces as opts.Backups asks.
+func (d TodoData) Save(path string, opts SaveOptions) error {
+	if err := refuseNewerSchema(path); err != nil {
+		return err
+	}
+	bytes, err := encodeDataFile(d)
 	if err != nil {
 		return err
 	}
@@ -150,14 +393,32 @@ func (d TodoData) Save(path string) error {
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
+	if err := backupFile(path, opts.Backups); err != nil {
+		return fmt.Errorf("backing up %s: %w", filepath.Base(path), err)
+	}
+	if err := WritePrivateFile(path, bytes); err != nil {
+		return err
+	}
 
-	temp, err := os.CreateTemp(dir, "doitdoit-*")
+	if opts.AfterSave != nil {
+		if err := opts.AfterSave(d); err != nil {
+			return fmt.Errorf("%w: %w", ErrAfterSave, err)
+		}
+	}
+	return nil
+}
+
+// WritePrivateFile replaces the file at path with raw, readable only by the
+// owner. It writes a temporary file beside it and renames that into place,
+// so a reader such as a sync client never sees half a file.
+func WritePrivateFile(path string, raw []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "doitdoit-*")
 	if err != nil {
 		return err
 	}
 	tempPath := temp.Name()
 
-	if _, err := temp.Write(bytes); err != nil {
+	if _, err := temp.Write(raw); err != nil {
 		temp.Close()
 		os.Remove(tempPath)
 		return err
@@ -182,59 +443,66 @@ func (d TodoData) Save(path string) error {
 		os.Remove(tempPath)
 		return err
 	}
-
 	return nil
 }
 
-func (d TodoData) pruneOldTasks(retentionDays int) bool {
+// pruneOldTasks drops completed history from more than retentionDays before
+// today.
+func (d TodoData) pruneOldTasks(retentionDays int, today time.Time) bool {
 	if retentionDays <= 0 {
 		return false
 	}
-	cutoff := time.Now().AddDate(0, 0, -retentionDays)
-	cutoffStr := cutoff.Format(dateLayout)
 	changed := false
 
-	for dateStr := range d {
-		if dateStr == "Future" {
-			// Prune completed tasks from Future
-			tasks := d[dateStr]
-			activeTasks := make([]Task, 0, len(tasks))
-			for _, t := range tasks {
-				if !t.Completed {
-					activeTasks = append(activeTasks, t)
-				}
-			}
-			if len(activeTasks) != len(tasks) {
-				changed = true
+	for dateStr, tasks := range d {
+		kept := make([]Task, 0, len(tasks))
+		for _, t := range tasks {
+			if !OutsideRetention(dateStr, t, retentionDays, today) {
+				kept = append(kept, t)
 			}
-			d[dateStr] = activeTasks
+		}
+		if len(kept) == len(tasks) {
 			continue
 		}
-		if dateStr < cutoffStr {
+		changed = true
+		if len(kept) == 0 && dateStr != FutureKey {
 			delete(d, dateStr)
-			changed = true
+		} else {
+			d[dateStr] = kept
 		}
 	}
 
 	return changed
 }
 
+// OutsideRetention reports whether pruning with retentionDays drops task from
+// the list under key: completed tasks leave Future, and days older than the
+// retention period before today go entirely. Zero days keeps everything.
+func OutsideRetention(key string, task Task, retentionDays int, today time.Time) bool {
+	if retentionDays <= 0 {
+		return false
+	}
+	if key == FutureKey {
+		return task.Completed
+	}
+	return key < today.AddDate(0, 0, -retentionDays).Format(dateLayout)
+}
+
 // DistributeFutureTasks moves tasks from "Future" to specific dates if they are
 // due within the initial viewport starting today.
-func (d TodoData) DistributeFutureTasks(visibleDays int) {
-	d.distributeFutureTasksThrough(startOfDay(time.Now()).AddDate(0, 0, visibleDays-1))
+func (d TodoData) DistributeFutureTasks(visibleDays int, today time.Time) {
+	d.distributeFutureTasksThrough(today.AddDate(0, 0, visibleDays-1), today)
 }
 
 // distributeFutureTasksThrough moves dated tasks out of Future once their date
-// has been loaded by the scrolling viewport. Undated tasks always remain in the
-// separate Future list.
-func (d TodoData) distributeFutureTasksThrough(lastVisible time.Time) bool {
+// has been loaded by the scrolling viewport, filing overdue ones on today.
+// Undated tasks always remain in the separate Future list.
+func (d TodoData) distributeFutureTasksThrough(lastVisible, today time.Time) bool {
 	futureTasks, ok := d["Future"]
 	if !ok || len(futureTasks) == 0 {
 		return false
 	}
 
-	today := startOfDay(time.Now())
 	todayStr := today.Format(dateLayout)
 
 	remainingFuture := make([]Task, 0)
@@ -246,7 +514,7 @@ func (d TodoData) distributeFutureTasksThrough(lastVisible time.Time) bool {
 			continue
 		}
 
-		dueDate, err := parseDate(task.DueDate)
+		dueDate, err := parseDate(task.DueDate, today.Location())
 		if err != nil {
 			remainingFuture = append(remainingFuture, task)
 			continue
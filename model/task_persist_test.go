@@ -5,7 +5,6 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
-	"time"
 )
 
 func TestLoadPersistsMutations(t *testing.T) {
@@ -13,7 +12,7 @@ func TestLoadPersistsMutations(t *testing.T) {
 	jsonPath := filepath.Join(tmpDir, "tasks.json")
 	importPath := filepath.Join(tmpDir, "import.txt")
 
-	sixDaysAgo := time.Now().AddDate(0, 0, -6).Format("2006-01-02")
+	sixDaysAgo := testNow().AddDate(0, 0, -6).Format("2006-01-02")
 	initial := TodoData{
 		sixDaysAgo: []Task{
 			{ID: "old", Title: "Old Task", Completed: false},
@@ -29,12 +28,12 @@ func TestLoadPersistsMutations(t *testing.T) {
 		t.Fatalf("write import file: %v", err)
 	}
 
-	data, err := Load(jsonPath, 0)
+	data, err := Load(jsonPath, 0, testCalendar().Today(), SaveOptions{})
 	if err != nil {
 		t.Fatalf("Load returned error: %v", err)
 	}
 
-	today := time.Now().Format("2006-01-02")
+	today := testNow().Format("2006-01-02")
 
 	if tasks := data[sixDaysAgo]; len(tasks) != 1 || tasks[0].ID != "done" {
 		t.Fatalf("expected completed history to be kept forever, got %#v", tasks)
@@ -46,14 +45,10 @@ func TestLoadPersistsMutations(t *testing.T) {
 		t.Fatalf("expected import file to remain untouched, got %q, err=%v", got, err)
 	}
 
-	persistedBytes, err := os.ReadFile(jsonPath)
+	persisted, err := loadRaw(jsonPath)
 	if err != nil {
 		t.Fatalf("read persisted json: %v", err)
 	}
-	var persisted TodoData
-	if err := json.Unmarshal(persistedBytes, &persisted); err != nil {
-		t.Fatalf("unmarshal persisted json: %v", err)
-	}
 
 	if tasks := persisted[sixDaysAgo]; len(tasks) != 1 || tasks[0].ID != "done" {
 		t.Fatalf("persisted data lost completed history: %#v", tasks)
@@ -69,13 +64,13 @@ func TestLoadPersistsMutations(t *testing.T) {
 func TestLoadPrunesOnlyWithPositiveRetention(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "tasks.json")
-	oldDate := time.Now().AddDate(0, 0, -31).Format(dateLayout)
+	oldDate := testNow().AddDate(0, 0, -31).Format(dateLayout)
 	payload, _ := json.Marshal(TodoData{oldDate: {{ID: "done", Completed: true}}})
 	if err := os.WriteFile(path, payload, 0600); err != nil {
 		t.Fatal(err)
 	}
 
-	data, err := Load(path, 30)
+	data, err := Load(path, 30, testCalendar().Today(), SaveOptions{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -5,7 +5,6 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
-	"time"
 )
 
 func TestImportTextIsIgnoredAndPreserved(t *testing.T) {
@@ -22,7 +21,7 @@ func TestImportTextIsIgnoredAndPreserved(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	data, err := Load(jsonPath, 0)
+	data, err := Load(jsonPath, 0, testCalendar().Today(), SaveOptions{})
 	if err != nil {
 		t.Fatalf("Load failed: %v", err)
 	}
@@ -39,7 +38,7 @@ func TestImportTextIsIgnoredAndPreserved(t *testing.T) {
 }
 
 func TestPruningAndRollover(t *testing.T) {
-	today := time.Now()
+	today := testNow()
 	todayStr := today.Format("2006-01-02")
 
 	sixDaysAgo := today.AddDate(0, 0, -6).Format("2006-01-02")
@@ -61,8 +60,8 @@ func TestPruningAndRollover(t *testing.T) {
 	}
 
 	// Execute the logic in the same order as Load()
-	_ = data.rollOverIncompleteTasks()
-	_ = data.pruneOldTasks(5)
+	_ = data.rollOverIncompleteTasks(testCalendar().Today())
+	_ = data.pruneOldTasks(5, testCalendar().Today())
 
 	// Assertions
 
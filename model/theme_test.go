@@ -3,7 +3,6 @@ package model
 import (
 	"strings"
 	"testing"
-	"time"
 
 	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
@@ -15,6 +14,7 @@ func TestThemeReloadMsgAppliesTheme(t *testing.T) {
 	t.Cleanup(func() { styles.Apply(styles.DefaultTheme()) })
 
 	m := Model{
+		Calendar:  testCalendar(),
 		State:     Browsing,
 		TextInput: textinput.New(),
 	}
@@ -42,8 +42,9 @@ func TestThemeReloadMsgAppliesTheme(t *testing.T) {
 func TestBuiltinOmarchyThemesRenderWithBubbleTeaV2(t *testing.T) {
 	t.Cleanup(func() { styles.Apply(styles.DefaultTheme()) })
 
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Themed task"}}},
 		VisibleDays: 1,
 		State:       Browsing,
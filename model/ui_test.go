@@ -26,11 +26,12 @@ func nextWeekday(after time.Time, weekday time.Weekday) time.Time {
 }
 
 func TestMovePickerMovesFridayTaskToMonday(t *testing.T) {
-	friday := nextWeekday(time.Now(), time.Friday)
+	friday := nextWeekday(testNow(), time.Friday)
 	monday := friday.AddDate(0, 0, 3)
 	fridayKey := friday.Format(dateLayout)
 	mondayKey := monday.Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{fridayKey: {{ID: "1", Title: "Task 1"}}},
 		VisibleDays: 14,
 		State:       Browsing,
@@ -52,8 +53,9 @@ func TestMovePickerMovesFridayTaskToMonday(t *testing.T) {
 }
 
 func TestMovePickerFutureOffsetUsesToday(t *testing.T) {
-	tomorrow := time.Now().AddDate(0, 0, 1).Format(dateLayout)
+	tomorrow := testNow().AddDate(0, 0, 1).Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{"Future": {{ID: "1", Title: "Task 1"}}},
 		VisibleDays: 3,
 		State:       Browsing,
@@ -71,9 +73,10 @@ func TestMovePickerFutureOffsetUsesToday(t *testing.T) {
 }
 
 func TestMoveBeyondVisibleWindowIsHeldInFuture(t *testing.T) {
-	today := time.Now().Format(dateLayout)
-	target := time.Now().AddDate(0, 0, 7).Format(dateLayout)
+	today := testNow().Format(dateLayout)
+	target := testNow().AddDate(0, 0, 7).Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Task 1"}}},
 		VisibleDays: 3,
 		State:       Browsing,
@@ -88,8 +91,9 @@ func TestMoveBeyondVisibleWindowIsHeldInFuture(t *testing.T) {
 }
 
 func TestMoveToFutureClearsDueDate(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Task 1", DueDate: today}}},
 		VisibleDays: 3,
 		State:       Browsing,
@@ -104,9 +108,10 @@ func TestMoveToFutureClearsDueDate(t *testing.T) {
 }
 
 func TestRepeatMoveUsesAbsoluteDestination(t *testing.T) {
-	today := time.Now().Format(dateLayout)
-	target := time.Now().AddDate(0, 0, 2).Format(dateLayout)
+	today := testNow().Format(dateLayout)
+	target := testNow().AddDate(0, 0, 2).Format(dateLayout)
 	m := Model{
+		Calendar: testCalendar(),
 		Data: TodoData{today: {
 			{ID: "1", Title: "Task 1"},
 			{ID: "2", Title: "Task 2"},
@@ -125,9 +130,10 @@ func TestRepeatMoveUsesAbsoluteDestination(t *testing.T) {
 }
 
 func TestUndoRestoresLastMoveAndFocus(t *testing.T) {
-	today := time.Now().Format(dateLayout)
-	tomorrow := time.Now().AddDate(0, 0, 1).Format(dateLayout)
+	today := testNow().Format(dateLayout)
+	tomorrow := testNow().AddDate(0, 0, 1).Format(dateLayout)
 	m := Model{
+		Calendar: testCalendar(),
 		Data: TodoData{today: {
 			{ID: "1", Title: "Task 1"},
 			{ID: "2", Title: "Task 2"},
@@ -144,8 +150,8 @@ func TestUndoRestoresLastMoveAndFocus(t *testing.T) {
 	if got := m.Data[today]; len(got) != 2 || got[1].ID != "2" {
 		t.Fatalf("expected original source ordering, got %v", got)
 	}
-	if len(m.Data[tomorrow]) != 0 || m.RowIdx != 1 || m.moveUndo != nil {
-		t.Fatalf("expected destination cleared and focus restored, row=%d undo=%v", m.RowIdx, m.moveUndo)
+	if len(m.Data[tomorrow]) != 0 || m.RowIdx != 1 || len(m.undoStack) != 0 {
+		t.Fatalf("expected destination cleared and focus restored, row=%d undo=%d", m.RowIdx, len(m.undoStack))
 	}
 	if m.lastMoveTarget == nil || m.lastMoveTarget.Date != tomorrow {
 		t.Fatal("expected undo to preserve the repeat destination")
@@ -153,8 +159,9 @@ func TestUndoRestoresLastMoveAndFocus(t *testing.T) {
 }
 
 func TestReorderAndUndo(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := Model{
+		Calendar: testCalendar(),
 		Data: TodoData{today: {
 			{ID: "1", Title: "Task 1"},
 			{ID: "2", Title: "Task 2"},
@@ -175,9 +182,10 @@ func TestReorderAndUndo(t *testing.T) {
 }
 
 func TestCompletedTasksStayBelowMovedIncompleteTask(t *testing.T) {
-	today := time.Now().Format(dateLayout)
-	tomorrow := time.Now().AddDate(0, 0, 1).Format(dateLayout)
+	today := testNow().Format(dateLayout)
+	tomorrow := testNow().AddDate(0, 0, 1).Format(dateLayout)
 	m := Model{
+		Calendar: testCalendar(),
 		Data: TodoData{
 			today:    {{ID: "move", Title: "Move"}},
 			tomorrow: {{ID: "done", Title: "Done", Completed: true}},
@@ -194,14 +202,14 @@ func TestCompletedTasksStayBelowMovedIncompleteTask(t *testing.T) {
 }
 
 func TestLegacyMoveAliasesDoNothing(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	for _, showFuture := range []bool{false, true} {
 		for _, key := range []rune{'>', 't', 'T'} {
 			data := TodoData{
 				today:    {{ID: "dated", Title: "Dated"}},
 				"Future": {{ID: "future", Title: "Future"}},
 			}
-			m := Model{Data: data, VisibleDays: 3, State: Browsing, ShowFuture: showFuture, dateKeys: []string{today}}
+			m := Model{Calendar: testCalendar(), Data: data, VisibleDays: 3, State: Browsing, ShowFuture: showFuture, dateKeys: []string{today}}
 			m = pressRune(m, key)
 			if len(m.Data[today]) != 1 || len(m.Data["Future"]) != 1 || m.State != Browsing {
 				t.Fatalf("expected legacy key %q to do nothing in Future=%v", key, showFuture)
@@ -211,8 +219,9 @@ func TestLegacyMoveAliasesDoNothing(t *testing.T) {
 }
 
 func TestSameDestinationClosesPickerWithoutChangingHistory(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Task 1", DueDate: today}}},
 		VisibleDays: 1,
 		State:       Browsing,
@@ -220,14 +229,15 @@ func TestSameDestinationClosesPickerWithoutChangingHistory(t *testing.T) {
 	}
 
 	m = pressRune(pressRune(m, 'm'), 't')
-	if m.State != Browsing || m.moveUndo != nil || m.lastMoveTarget != nil {
+	if m.State != Browsing || len(m.undoStack) != 0 || m.lastMoveTarget != nil {
 		t.Fatalf("expected same-date target to close without history, got state=%v", m.State)
 	}
 }
 
 func TestMoveDateEscapeReturnsToPicker(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Task 1"}}},
 		VisibleDays: 1,
 		State:       Browsing,
@@ -244,9 +254,10 @@ func TestMoveDateEscapeReturnsToPicker(t *testing.T) {
 }
 
 func TestMoveDateInputSchedulesExactDate(t *testing.T) {
-	today := time.Now().Format(dateLayout)
-	target := time.Now().AddDate(0, 0, 10).Format(dateLayout)
+	today := testNow().Format(dateLayout)
+	target := testNow().AddDate(0, 0, 10).Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Task 1"}}},
 		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
 		VisibleDays: 3,
@@ -270,39 +281,21 @@ func TestMoveDateInputSchedulesExactDate(t *testing.T) {
 	}
 }
 
-func TestNonMoveMutationClearsUndo(t *testing.T) {
-	today := time.Now().Format(dateLayout)
-	m := Model{
-		Data:        TodoData{today: {{ID: "1", Title: "Task 1"}, {ID: "2", Title: "Task 2"}}},
-		VisibleDays: 2,
-		State:       Browsing,
-		dateKeys:    []string{today},
-	}
-	m = pressRune(pressRune(m, 'm'), '1')
-	if m.moveUndo == nil {
-		t.Fatal("expected move to create undo history")
-	}
-	m = pressRune(m, ' ')
-	if m.moveUndo != nil {
-		t.Fatal("expected task mutation to clear move undo history")
-	}
-}
-
 func TestMoveAndRepeatNoOpWithoutTaskOrTarget(t *testing.T) {
-	today := time.Now().Format(dateLayout)
-	m := Model{Data: TodoData{today: {}}, VisibleDays: 1, State: Browsing, dateKeys: []string{today}}
+	today := testNow().Format(dateLayout)
+	m := Model{Calendar: testCalendar(), Data: TodoData{today: {}}, VisibleDays: 1, State: Browsing, dateKeys: []string{today}}
 	m = pressRune(m, 'm')
-	if m.State != Browsing || m.moveUndo != nil || m.lastMoveTarget != nil {
+	if m.State != Browsing || len(m.undoStack) != 0 || m.lastMoveTarget != nil {
 		t.Fatalf("expected empty move to be a no-op, got state=%v", m.State)
 	}
 	m = pressRune(m, '.')
-	if m.moveUndo != nil || m.lastMoveTarget != nil {
+	if len(m.undoStack) != 0 || m.lastMoveTarget != nil {
 		t.Fatal("expected repeat without a destination to be a no-op")
 	}
 }
 
 func TestBrowsingHelpUsesCompactModalHint(t *testing.T) {
-	mainHelp := (Model{State: Browsing}).helpView()
+	mainHelp := (Model{Calendar: testCalendar(), State: Browsing}).helpView()
 	if !strings.Contains(mainHelp, "help") || !strings.Contains(mainHelp, "?") {
 		t.Fatalf("expected compact help hint, got %q", mainHelp)
 	}
@@ -310,21 +303,21 @@ func TestBrowsingHelpUsesCompactModalHint(t *testing.T) {
 		t.Fatalf("expected shortcut details to stay out of the footer, got %q", mainHelp)
 	}
 
-	mainModal := (Model{State: Browsing, width: 80}).helpModalView()
+	mainModal := (Model{Calendar: testCalendar(), State: Browsing, width: 80}).helpModalView()
 	if !strings.Contains(mainModal, "Future view") || !strings.Contains(mainModal, "arrows / hjkl") {
 		t.Fatalf("expected main modal to describe Future toggle and full navigation, got %q", mainModal)
 	}
 
-	futureModal := (Model{State: Browsing, ShowFuture: true, width: 80}).helpModalView()
+	futureModal := (Model{Calendar: testCalendar(), State: Browsing, ShowFuture: true, width: 80}).helpModalView()
 	if !strings.Contains(futureModal, "main view") || !strings.Contains(futureModal, "↑/↓ / k/j") {
 		t.Fatalf("expected Future modal to describe main-view toggle and vertical navigation, got %q", futureModal)
 	}
 }
 
 func TestMoveFooterShowsExactTargetsInFutureView(t *testing.T) {
-	base := startOfDay(time.Now())
+	base := testCalendar().Today()
 	for _, width := range []int{80, 48} {
-		m := Model{State: ChoosingMoveDestination, ShowFuture: true, width: width}
+		m := Model{Calendar: testCalendar(), State: ChoosingMoveDestination, ShowFuture: true, width: width}
 		help := m.helpView()
 		for days := 1; days <= 7; days++ {
 			label := base.AddDate(0, 0, days).Format("Mon 02")
@@ -340,7 +333,7 @@ func TestMoveFooterShowsExactTargetsInFutureView(t *testing.T) {
 		}
 	}
 
-	m := Model{State: ChoosingMoveDestination, ShowFuture: true, width: 80}
+	m := Model{Calendar: testCalendar(), State: ChoosingMoveDestination, ShowFuture: true, width: 80}
 	m.ShowHelp = false
 	m = pressRune(m, '?')
 	if m.ShowHelp {
@@ -349,8 +342,9 @@ func TestMoveFooterShowsExactTargetsInFutureView(t *testing.T) {
 }
 
 func TestHelpModalCapturesInputUntilClosed(t *testing.T) {
-	today := time.Now().Format(dateLayout)
+	today := testNow().Format(dateLayout)
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Task"}}},
 		VisibleDays: 1,
 		State:       Browsing,
@@ -0,0 +1,109 @@
+package model
+
+import "fmt"
+
+// maxUndo bounds the session's undo history; each entry holds a full copy of
+// the data, which stays small for a personal task file.
+const maxUndo = 50
+
+// undoEntry restores the data and focus from one side of a change. Label
+// names the change for the footer, e.g. `delete "Buy milk"`.
+type undoEntry struct {
+	Data       TodoData
+	ShowFuture bool
+	FirstDay   string
+	ColIdx     int
+	RowIdx     int
+	Label      string
+}
+
+func (m Model) undoSnapshot(label string) undoEntry {
+	firstDay := ""
+	if len(m.dateKeys) > 0 {
+		firstDay = m.dateKeys[0]
+	}
+	return undoEntry{
+		Data:       cloneTodoData(m.Data),
+		ShowFuture: m.ShowFuture,
+		FirstDay:   firstDay,
+		ColIdx:     m.ColIdx,
+		RowIdx:     m.RowIdx,
+		Label:      label,
+	}
+}
+
+// recordUndo snapshots the state before a change. A new change forks
+// history, so anything that could have been redone is discarded.
+func (m *Model) recordUndo(label string) {
+	m.undoStack = pushBounded(m.undoStack, m.undoSnapshot(label))
+	m.redoStack = nil
+}
+
+// clearUndo drops all history, used when the data changes underneath the
+// session (an external reload or the midnight rollover) and old snapshots
+// would resurrect stale state.
+func (m *Model) clearUndo() {
+	m.undoStack = nil
+	m.redoStack = nil
+}
+
+func (m *Model) undo() bool {
+	if len(m.undoStack) == 0 {
+		return false
+	}
+	entry := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.redoStack = pushBounded(m.redoStack, m.undoSnapshot(entry.Label))
+	m.restore(entry)
+	m.status = fmt.Sprintf("Undid %s", entry.Label)
+	return true
+}
+
+func (m *Model) redo() bool {
+	if len(m.redoStack) == 0 {
+		return false
+	}
+	entry := m.redoStack[len(m.redoStack)-1]
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+	m.undoStack = pushBounded(m.undoStack, m.undoSnapshot(entry.Label))
+	m.restore(entry)
+	m.status = fmt.Sprintf("Redid %s", entry.Label)
+	return true
+}
+
+// restore swaps in a snapshot, scrolling back to the day window it was taken
+// in unless that window now starts before today.
+func (m *Model) restore(entry undoEntry) {
+	m.Data = cloneTodoData(entry.Data)
+	m.ShowFuture = entry.ShowFuture
+	if len(m.dateKeys) > 0 && entry.FirstDay != m.dateKeys[0] {
+		today := m.Calendar.Today()
+		if firstDay, err := parseDate(entry.FirstDay, today.Location()); err == nil && !firstDay.Before(today) {
+			m.updateDateKeysFrom(firstDay)
+		}
+	}
+	m.ColIdx = entry.ColIdx
+	if m.ColIdx >= len(m.dateKeys) {
+		m.ColIdx = max(0, len(m.dateKeys)-1)
+	}
+	m.RowIdx = entry.RowIdx
+	m.clampRow()
+}
+
+func pushBounded(stack []undoEntry, entry undoEntry) []undoEntry {
+	stack = append(stack, entry)
+	if len(stack) > maxUndo {
+		stack = stack[len(stack)-maxUndo:]
+	}
+	return stack
+}
+
+// selectedTask returns the task under the cursor. A cursor left on a task the
+// filter hides selects nothing, so actions never touch what is off screen.
+func (m Model) selectedTask() (Task, bool) {
+	key := m.getCurrentKey()
+	if !m.rowVisible(key, m.RowIdx) {
+		return Task{}, false
+	}
+	return m.Data[key][m.RowIdx], true
+}
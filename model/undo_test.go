@@ -0,0 +1,145 @@
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+func newUndoTestModel(t *testing.T) Model {
+	t.Helper()
+	today := testNow().Format(dateLayout)
+	return Model{
+		Calendar: testCalendar(),
+		Data: TodoData{today: {
+			{ID: "1", Title: "Task 1"},
+			{ID: "2", Title: "Task 2"},
+		}},
+		FilePath:    filepath.Join(t.TempDir(), "tasks.json"),
+		VisibleDays: 1,
+		State:       Browsing,
+		TextInput:   textinput.New(),
+		dateKeys:    []string{today},
+	}
+}
+
+func titles(tasks []Task) string {
+	parts := make([]string, len(tasks))
+	for i, task := range tasks {
+		mark := ""
+		if task.Completed {
+			mark = "x"
+		}
+		parts[i] = mark + task.Title
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestUndoStepsBackThroughMixedChangesAndRedoReplays(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	m := newUndoTestModel(t)
+
+	m = pressRune(m, ' ') // complete Task 1
+	m = pressRune(m, 'd') // delete Task 2, now at the top
+	m = pressRune(m, 'a')
+	m.TextInput.SetValue("Task 3")
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+
+	if got := titles(m.Data[today]); got != "Task 3,xTask 1" {
+		t.Fatalf("setup produced %q", got)
+	}
+
+	steps := []struct{ want, status string }{
+		{"xTask 1", `Undid add "Task 3"`},
+		{"Task 2,xTask 1", `Undid delete "Task 2"`},
+		{"Task 1,Task 2", `Undid complete "Task 1"`},
+	}
+	for _, step := range steps {
+		m = pressRune(m, 'u')
+		if got := titles(m.Data[today]); got != step.want {
+			t.Fatalf("after undo got %q, want %q", got, step.want)
+		}
+		if m.status != step.status {
+			t.Errorf("status = %q, want %q", m.status, step.status)
+		}
+	}
+	if m = pressRune(m, 'u'); m.status != "Nothing to undo" {
+		t.Errorf("expected empty history message, got %q", m.status)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl})
+	m = updated.(Model)
+	if got := titles(m.Data[today]); got != "Task 2,xTask 1" || m.status != `Redid complete "Task 1"` {
+		t.Fatalf("redo got %q status %q", got, m.status)
+	}
+
+	loaded, err := Load(m.FilePath, 0, testCalendar().Today(), SaveOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(loaded[today]); got != "Task 2,xTask 1" {
+		t.Errorf("undo and redo should persist, file has %q", got)
+	}
+}
+
+func TestNewChangeDiscardsRedo(t *testing.T) {
+	m := pressRune(pressRune(newUndoTestModel(t), ' '), 'u')
+	if len(m.redoStack) != 1 {
+		t.Fatalf("expected one redo entry, got %d", len(m.redoStack))
+	}
+	m = pressRune(m, 'd')
+	if len(m.redoStack) != 0 || len(m.undoStack) != 1 {
+		t.Fatalf("expected a new change to drop redo, got undo=%d redo=%d", len(m.undoStack), len(m.redoStack))
+	}
+}
+
+func TestUndoHistoryIsBounded(t *testing.T) {
+	m := newUndoTestModel(t)
+	for i := range maxUndo + 10 {
+		m.addTask(fmt.Sprintf("Task %d", i))
+	}
+	if len(m.undoStack) != maxUndo {
+		t.Fatalf("undo stack = %d entries, want %d", len(m.undoStack), maxUndo)
+	}
+	if got := m.undoStack[0].Label; got != `add "Task 10"` {
+		t.Errorf("expected the oldest entries dropped, first is %s", got)
+	}
+}
+
+func TestStatusShowsInFooterUntilNextKey(t *testing.T) {
+	m := pressRune(pressRune(newUndoTestModel(t), ' '), 'u')
+	m.width, m.height = 100, 30
+	if view := m.View().Content; !strings.Contains(view, `Undid complete "Task 1"`) {
+		t.Fatalf("expected undo message in the footer, got %q", view)
+	}
+	if m = pressRune(m, 'j'); m.status != "" {
+		t.Errorf("expected the next key to clear the message, got %q", m.status)
+	}
+}
+
+func TestExternalReloadClearsUndoHistory(t *testing.T) {
+	today := testNow().Format(dateLayout)
+	m := pressRune(newUndoTestModel(t), ' ')
+	m = pressRune(m, 'u')
+	m = pressRune(m, 'd')
+
+	external := TodoData{today: {{ID: "9", Title: "From phone"}}}
+	updated, _ := m.Update(dataFileCheckedMsg{data: external, modTime: time.Now(), size: 1})
+	m = updated.(Model)
+
+	if len(m.undoStack) != 0 || len(m.redoStack) != 0 {
+		t.Fatalf("expected reload to drop history, got undo=%d redo=%d", len(m.undoStack), len(m.redoStack))
+	}
+	if !strings.Contains(m.status, "Undo history cleared") {
+		t.Errorf("expected a footer note about the dropped history, got %q", m.status)
+	}
+	if m = pressRune(m, 'u'); titles(m.Data[today]) != "From phone" {
+		t.Errorf("undo after reload must not resurrect stale data, got %v", m.Data[today])
+	}
+}
@@ -1,7 +1,10 @@
 package model
 
 import (
+	"fmt"
+	"strings"
 	"time"
+	"unicode"
 
 	tea "charm.land/bubbletea/v2"
 )
@@ -57,6 +60,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleReloadTick()
 	case dataFileCheckedMsg:
 		return m.handleDataFileChecked(msg)
+	case notesEditedMsg:
+		return m.handleNotesEdited(msg)
 	case ThemeReloadMsg:
 		return m.handleThemeReload(msg)
 	case tea.KeyPressMsg:
@@ -69,7 +74,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleMouseClick(msg tea.MouseClickMsg) (tea.Model, tea.Cmd) {
-	if m.ShowHelp || msg.Button != tea.MouseLeft {
+	if m.ShowHelp || m.State == ViewingDetails || m.State == Searching || m.State == ResolvingConflicts || msg.Button != tea.MouseLeft {
 		return m, nil
 	}
 
@@ -84,27 +89,30 @@ func (m Model) handleMouseClick(msg tea.MouseClickMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleDateTick() (tea.Model, tea.Cmd) {
-	todayKey := time.Now().Format(dateLayout)
+	today := m.Calendar.Today()
+	todayKey := today.Format(dateLayout)
 	dayChanged := m.todayKey != "" && m.todayKey != todayKey
-	if m.todayKey == "" && (len(m.dateKeys) == 0 || m.firstVisibleDate().Before(startOfDay(time.Now()))) {
+	if m.todayKey == "" && (len(m.dateKeys) == 0 || m.firstVisibleDate().Before(today)) {
 		dayChanged = true
 	}
 	if dayChanged {
+		// The history window is measured back from today; start afresh.
+		m.history = false
 		focusedDate := ""
 		if !m.ShowFuture && m.ColIdx >= 0 && m.ColIdx < len(m.dateKeys) {
 			focusedDate = m.dateKeys[m.ColIdx]
 		}
 
-		m.Data.rollOverIncompleteTasks()
-		m.Data.pruneOldTasks(m.RetentionDays)
-		m.clearMoveUndo()
+		m.Data.rollOverIncompleteTasks(today)
+		m.Data.pruneOldTasks(m.RetentionDays, today)
+		m.clearUndo()
 		firstDay := m.firstVisibleDate()
-		if firstDay.Before(startOfDay(time.Now())) {
-			firstDay = startOfDay(time.Now())
+		if firstDay.Before(today) {
+			firstDay = today
 		}
 		m.updateDateKeysFrom(firstDay)
 		m.todayKey = todayKey
-		m.Data.distributeFutureTasksThrough(m.lastVisibleDate())
+		m.Data.distributeFutureTasksThrough(m.lastVisibleDate(), today)
 		m.ColIdx = 0
 		for i, dateKey := range m.dateKeys {
 			if dateKey == focusedDate {
@@ -135,6 +143,7 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		m.ShowHelp = true
 		return m, nil
 	}
+	m.status = ""
 
 	switch m.State {
 	case Adding:
@@ -145,6 +154,20 @@ func (m Model) handleKeyMsg(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		return m.handleChoosingMoveDestinationKey(msg)
 	case SettingMoveDate:
 		return m.handleSettingMoveDateKey(msg)
+	case Editing:
+		return m.handleEditingKey(msg)
+	case ViewingDetails:
+		return m.handleViewingDetailsKey(msg)
+	case SettingRepeat:
+		return m.handleSettingRepeatKey(msg)
+	case SettingDeadline:
+		return m.handleSettingDeadlineKey(msg)
+	case Filtering:
+		return m.handleFilteringKey(msg)
+	case Searching:
+		return m.handleSearchingKey(msg)
+	case ResolvingConflicts:
+		return m.handleResolvingConflictsKey(msg)
 	default:
 		return m, nil
 	}
@@ -154,7 +177,6 @@ func (m Model) handleAddingKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	switch msg.Code {
 	case tea.KeyEnter:
 		if m.TextInput.Value() != "" {
-			m.clearMoveUndo()
 			m.addTask(m.TextInput.Value())
 			m.TextInput.Reset()
 			m.State = Browsing
@@ -172,7 +194,43 @@ func (m Model) handleAddingKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleEditingKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.Code {
+	case tea.KeyEnter:
+		title := strings.TrimSpace(m.TextInput.Value())
+		if title == "" {
+			m.Err = fmt.Errorf("task title cannot be empty")
+			return m, nil
+		}
+		m.Err = nil
+		edited := m.editTask(title)
+		m.TextInput.Reset()
+		m.State = Browsing
+		if edited {
+			m.persist()
+		}
+	case tea.KeyEsc:
+		m.TextInput.Reset()
+		m.Err = nil
+		m.State = Browsing
+	default:
+		var cmd tea.Cmd
+		m.TextInput, cmd = m.TextInput.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
 func (m Model) handleBrowsingKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if m.history {
+		next, cmd, handled := m.handleHistoryKey(msg)
+		if handled {
+			return next, cmd
+		}
+		m = next
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		return m, tea.Quit
@@ -181,7 +239,7 @@ func (m Model) handleBrowsingKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			if m.ColIdx < len(m.dateKeys)-1 {
 				m.ColIdx++
 			} else if m.shiftDateWindow(1) {
-				if m.Data.distributeFutureTasksThrough(m.lastVisibleDate()) {
+				if m.Data.distributeFutureTasksThrough(m.lastVisibleDate(), m.Calendar.Today()) {
 					m.persist()
 				}
 			}
@@ -197,31 +255,72 @@ func (m Model) handleBrowsingKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			m.clampRow()
 		}
 	case "up", "k":
-		if m.RowIdx > 0 {
-			m.RowIdx--
-		}
+		m.RowIdx, _ = m.nextVisibleRow(m.getCurrentKey(), m.RowIdx, -1)
 	case "down", "j":
-		currentDate := m.getCurrentKey()
-		if m.RowIdx < len(m.Data[currentDate])-1 {
-			m.RowIdx++
-		}
+		m.RowIdx, _ = m.nextVisibleRow(m.getCurrentKey(), m.RowIdx, 1)
 	case "a":
 		m.State = Adding
 		m.configureTextInput("New task...")
 		return m, nil
+	case "e":
+		if task, ok := m.selectedTask(); ok {
+			m.State = Editing
+			m.configureTextInput("Task title...")
+			m.TextInput.SetValue(task.Title)
+			m.TextInput.CursorEnd()
+		}
+		return m, nil
+	case "/":
+		m.openSearch()
+		return m, nil
+	case "H":
+		m.enterHistory(m.Calendar.Today())
+	case "C":
+		if len(m.conflicts) > 0 {
+			m.State = ResolvingConflicts
+		} else {
+			m.status = "No conflicts to review"
+		}
+	case "#":
+		m.State = Filtering
+		m.configureTextInput("#tag or +project")
+		m.TextInput.SetValue(m.filter)
+		m.TextInput.CursorEnd()
+		return m, nil
+	case "esc":
+		if m.filter != "" {
+			m.filter = ""
+			m.clampRow()
+		}
+	case "i":
+		m.openDetails()
+		return m, nil
+	case "r":
+		if task, ok := m.selectedTask(); ok {
+			m.State = SettingRepeat
+			m.configureTextInput("daily, weekdays, weekly on mon,fri...")
+			m.TextInput.SetValue(task.Repeat)
+			m.TextInput.CursorEnd()
+		}
+		return m, nil
+	case "D":
+		if task, ok := m.selectedTask(); ok {
+			m.State = SettingDeadline
+			m.configureTextInput("YYYY-MM-DD or MM-DD")
+			m.TextInput.SetValue(task.Deadline)
+			m.TextInput.CursorEnd()
+		}
+		return m, nil
 	case "d":
 		if m.deleteTask() {
-			m.clearMoveUndo()
 			m.persist()
 		}
 	case "enter", "space":
 		if m.toggleTask() {
-			m.clearMoveUndo()
 			m.persist()
 		}
 	case "m":
-		currentKey := m.getCurrentKey()
-		if m.RowIdx >= 0 && m.RowIdx < len(m.Data[currentKey]) {
+		if _, ok := m.selectedTask(); ok {
 			m.State = ChoosingMoveDestination
 		}
 	case "J":
@@ -237,8 +336,16 @@ func (m Model) handleBrowsingKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 			m.persist()
 		}
 	case "u":
-		if m.undoMove() {
+		if m.undo() {
+			m.persist()
+		} else {
+			m.status = "Nothing to undo"
+		}
+	case "ctrl+r":
+		if m.redo() {
 			m.persist()
+		} else {
+			m.status = "Nothing to redo"
 		}
 	case "f":
 		m.ShowFuture = !m.ShowFuture
@@ -261,7 +368,7 @@ func (m Model) handleChoosingMoveDestinationKey(msg tea.KeyPressMsg) (tea.Model,
 	case "esc":
 		m.State = Browsing
 	case "t":
-		moved := m.scheduleTask(moveTarget{Date: time.Now().Format(dateLayout)})
+		moved := m.scheduleTask(moveTarget{Date: m.Calendar.Today().Format(dateLayout)})
 		m.State = Browsing
 		if moved {
 			m.persist()
@@ -291,7 +398,7 @@ func (m Model) handleChoosingMoveDestinationKey(msg tea.KeyPressMsg) (tea.Model,
 func (m Model) handleSettingMoveDateKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	switch msg.Code {
 	case tea.KeyEnter:
-		normalizedDate, err := normalizeDueDateInput(m.TextInput.Value())
+		normalizedDate, err := NormalizeDueDate(m.TextInput.Value(), m.Calendar.Today())
 		if err != nil {
 			m.Err = err
 			return m, nil
@@ -315,3 +422,105 @@ func (m Model) handleSettingMoveDateKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd
 
 	return m, nil
 }
+
+// handleSettingRepeatKey edits the selected task's recurrence rule. Clearing
+// the input, or typing "none", stops the task repeating.
+func (m Model) handleSettingRepeatKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.Code {
+	case tea.KeyEnter:
+		rule := strings.TrimSpace(m.TextInput.Value())
+		if strings.EqualFold(rule, "none") {
+			rule = ""
+		}
+		if rule != "" {
+			parsed, err := ParseRecurrence(rule)
+			if err != nil {
+				m.Err = err
+				return m, nil
+			}
+			rule = parsed.String()
+		}
+		m.Err = nil
+		changed := m.setRepeat(rule)
+		m.TextInput.Reset()
+		m.State = Browsing
+		if changed {
+			m.persist()
+		}
+	case tea.KeyEsc:
+		m.TextInput.Reset()
+		m.Err = nil
+		m.State = Browsing
+	default:
+		var cmd tea.Cmd
+		m.TextInput, cmd = m.TextInput.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// handleSettingDeadlineKey sets the selected task's deadline. Clearing the
+// input, or typing "none", removes it.
+func (m Model) handleSettingDeadlineKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.Code {
+	case tea.KeyEnter:
+		date := strings.TrimSpace(m.TextInput.Value())
+		if strings.EqualFold(date, "none") {
+			date = ""
+		}
+		if date != "" {
+			normalized, err := NormalizeDueDate(date, m.Calendar.Today())
+			if err != nil {
+				m.Err = err
+				return m, nil
+			}
+			date = normalized
+		}
+		m.Err = nil
+		changed := m.setDeadline(date)
+		m.TextInput.Reset()
+		m.State = Browsing
+		if changed {
+			m.persist()
+		}
+	case tea.KeyEsc:
+		m.TextInput.Reset()
+		m.Err = nil
+		m.State = Browsing
+	default:
+		var cmd tea.Cmd
+		m.TextInput, cmd = m.TextInput.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// handleFilteringKey sets the tag or project filter. A bare word matches
+// either; an empty value clears the filter.
+func (m Model) handleFilteringKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch msg.Code {
+	case tea.KeyEnter:
+		filter := strings.ToLower(strings.TrimSpace(m.TextInput.Value()))
+		if strings.ContainsFunc(filter, unicode.IsSpace) || filter == "#" || filter == "+" {
+			m.Err = fmt.Errorf("filter by a single #tag or +project")
+			return m, nil
+		}
+		m.Err = nil
+		m.filter = filter
+		m.TextInput.Reset()
+		m.State = Browsing
+		m.clampRow()
+	case tea.KeyEsc:
+		m.TextInput.Reset()
+		m.Err = nil
+		m.State = Browsing
+	default:
+		var cmd tea.Cmd
+		m.TextInput, cmd = m.TextInput.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"image/color"
 	"strings"
 	"time"
 
@@ -37,13 +38,19 @@ func (m Model) View() tea.View {
 	columns := m.renderColumns(keys, groups)
 
 	footer := m.helpView()
-	if errView := m.errorView(); errView != "" {
-		footer = errView + "\n" + footer
+	if notice := m.noticeView(); notice != "" {
+		footer = notice + "\n" + footer
 	}
 
 	content := styles.AppStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top, columns...) + "\n" + footer)
 	if m.ShowHelp {
 		content = m.renderHelpOverlay(content)
+	} else if m.State == ViewingDetails {
+		content = m.renderOverlay(content, m.detailsModalView())
+	} else if m.State == Searching {
+		content = m.renderOverlay(content, m.searchModalView())
+	} else if m.State == ResolvingConflicts {
+		content = m.renderOverlay(content, m.conflictsModalView())
 	}
 
 	view := tea.NewView(content)
@@ -119,7 +126,7 @@ func (m Model) renderColumns(keys []string, groups [][]int) []string {
 	// Render columns with unified height.
 	var columns []string
 	for i, content := range colContents {
-		isFocused := m.State != Adding && m.State != SettingMoveDate && m.groupFocused(groups[i])
+		isFocused := m.State != Editing && !m.typingBelowTasks() && m.groupFocused(groups[i])
 
 		style := styles.ColumnStyle.Width(columnBlockWidth).Height(columnBlockHeight)
 		if isFocused {
@@ -188,7 +195,7 @@ func (m Model) renderDaySection(dateStr string, dayIdx, colWidth int) string {
 	} else {
 		displayDate, _ := time.Parse("2006-01-02", dateStr)
 		header = displayDate.Format("Mon, Jan 02")
-		if dateStr == time.Now().Format("2006-01-02") {
+		if dateStr == m.Calendar.Today().Format(dateLayout) {
 			header = "Today"
 		}
 	}
@@ -202,8 +209,16 @@ func (m Model) renderDaySection(dateStr string, dayIdx, colWidth int) string {
 	// Tasks
 	var taskViews []string
 	tasks := m.Data[dateStr]
-
+	var visible []int
 	for j, task := range tasks {
+		if task.MatchesFilter(m.filter) {
+			visible = append(visible, j)
+		}
+	}
+
+	for n, j := range visible {
+		task := tasks[j]
+		last := n == len(visible)-1
 		var style lipgloss.Style
 		if task.Completed {
 			style = styles.CompletedTaskStyle
@@ -215,13 +230,31 @@ func (m Model) renderDaySection(dateStr string, dayIdx, colWidth int) string {
 		if m.ShowFuture && task.DueDate != "" {
 			title += fmt.Sprintf(" (%s)", task.DueDate)
 		}
+		if task.Repeat != "" {
+			title += " ↻"
+		}
+		if task.Notes != "" {
+			title += " ✎"
+		}
+
+		if m.State == Editing && (m.ShowFuture || m.ColIdx == dayIdx) && m.RowIdx == j {
+			// The title being edited is replaced in place by the input.
+			taskViews = append(taskViews, m.TextInput.View())
+			if !last {
+				taskViews = append(taskViews, "")
+			}
+			continue
+		}
 
+		colorLabels := !task.Completed
 		if isFocused && m.RowIdx == j {
 			if m.copyFlash {
 				style = style.Foreground(styles.Special).Bold(true)
+				colorLabels = false
 			} else if m.State == ChoosingMoveDestination {
 				// Use special moving style with highlight background
 				style = styles.MovingTaskStyle
+				colorLabels = false
 			} else {
 				// Normal selection highlight
 				style = style.Foreground(styles.Highlight).Bold(true)
@@ -234,35 +267,91 @@ func (m Model) renderDaySection(dateStr string, dayIdx, colWidth int) string {
 			titleWidth = 1
 		}
 
-		taskViews = append(taskViews, style.Width(titleWidth).Render(title))
+		taskViews = append(taskViews, renderTaskTitle(title, style, titleWidth, colorLabels))
+		if badges := m.renderTaskBadges(task); badges != "" {
+			taskViews = append(taskViews, lipgloss.NewStyle().Width(titleWidth).Render(badges))
+		}
 
 		// Add a blank line between tasks
-		if j < len(tasks)-1 {
+		if !last {
 			taskViews = append(taskViews, "")
 		}
 	}
 
 	// Input field if adding to this day
-	if (m.State == Adding || m.State == SettingMoveDate) && (m.ShowFuture || m.ColIdx == dayIdx) {
+	if m.typingBelowTasks() && (m.ShowFuture || m.ColIdx == dayIdx) {
 		// Add spacing before input if there are tasks
-		if len(tasks) > 0 {
+		if len(visible) > 0 {
 			taskViews = append(taskViews, "")
 		}
 
 		// Match TaskStyle padding
 		inputStyle := lipgloss.NewStyle()
 		prefix := ""
-		if m.State == SettingMoveDate {
+		switch m.State {
+		case SettingMoveDate:
 			prefix = "Move to: "
+		case SettingRepeat:
+			prefix = "Repeat: "
+		case SettingDeadline:
+			prefix = "Deadline: "
+		case Filtering:
+			prefix = "Filter: "
 		}
 		taskViews = append(taskViews, inputStyle.Render(prefix+m.TextInput.View()))
-	} else if len(tasks) == 0 {
-		taskViews = append(taskViews, lipgloss.NewStyle().Foreground(styles.Subtle).Render("No tasks"))
+	} else if len(visible) == 0 {
+		empty := "No tasks"
+		if len(tasks) > 0 {
+			empty = "No matching tasks"
+		}
+		taskViews = append(taskViews, lipgloss.NewStyle().Foreground(styles.Subtle).Render(empty))
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, title, lipgloss.JoinVertical(lipgloss.Left, taskViews...))
 }
 
+// renderTaskTitle wraps a task title to width. When colorLabels is set, #tag
+// and +project tokens take the tag colour while keeping the row's weight.
+func renderTaskTitle(title string, style lipgloss.Style, width int, colorLabels bool) string {
+	spans := SplitLabels(title)
+	hasLabel := false
+	for _, span := range spans {
+		hasLabel = hasLabel || span.Label
+	}
+	if !colorLabels || !hasLabel {
+		return style.Width(width).Render(title)
+	}
+
+	var rendered strings.Builder
+	for _, span := range spans {
+		spanStyle := style
+		if span.Label {
+			spanStyle = style.Foreground(styles.Tag)
+		}
+		rendered.WriteString(spanStyle.Render(span.Text))
+	}
+	return lipgloss.NewStyle().Width(width).Render(rendered.String())
+}
+
+// renderTaskBadges renders the line under a task's title: its deadline and,
+// once it has gone stale, how often it has rolled over. It is empty when
+// there is nothing to show.
+func (m Model) renderTaskBadges(task Task) string {
+	subtle := lipgloss.NewStyle().Foreground(styles.Subtle)
+	var badges []string
+	if badge, overdue := task.DeadlineBadge(m.Calendar.Today()); badge != "" {
+		badgeStyle := subtle
+		if overdue {
+			badgeStyle = lipgloss.NewStyle().Foreground(styles.Warning).Bold(true)
+		}
+		badges = append(badges, badgeStyle.Render("⚑ "+badge))
+	}
+	if m.StaleAfter > 0 && task.RolledOverMoreThan(m.StaleAfter) {
+		badges = append(badges, subtle.Faint(true).Render(fmt.Sprintf("⋯ rolled %d×", task.Rollovers)))
+	}
+	return strings.Join(badges, subtle.Render(" · "))
+}
+
 // isWeekend reports whether the YYYY-MM-DD date string falls on a weekend.
 func isWeekend(dateStr string) bool {
 	d, err := time.Parse("2006-01-02", dateStr)
@@ -273,6 +362,16 @@ func isWeekend(dateStr string) bool {
 	return wd == time.Saturday || wd == time.Sunday
 }
 
+// typingBelowTasks reports whether the text input sits below the focused
+// day's tasks rather than replacing one of them.
+func (m Model) typingBelowTasks() bool {
+	switch m.State {
+	case Adding, SettingMoveDate, SettingRepeat, SettingDeadline, Filtering:
+		return true
+	}
+	return false
+}
+
 func (m Model) helpView() string {
 	key := func(k string) string {
 		return styles.KeyStyle.Render(k)
@@ -286,7 +385,30 @@ func (m Model) helpView() string {
 
 	brand := m.brandView()
 	if m.State == Browsing {
-		return styles.HelpStyle.Render(brand + desc(". Press ") + key("?") + desc(" for help"))
+		hint := brand + desc(". Press ") + key("?") + desc(" for help")
+		if m.history {
+			hint += desc(". History is read-only, ") + key("H") + desc(" to return")
+		}
+		if n := len(m.conflicts); n > 0 {
+			noun := "conflicts"
+			if n == 1 {
+				noun = "conflict"
+			}
+			hint += desc(". ") + lipgloss.NewStyle().Foreground(styles.Warning).Render(fmt.Sprintf("%d sync %s", n, noun)) +
+				desc(", ") + key("C") + desc(" to review")
+		}
+		if n := len(m.conflictedCopies); n > 0 {
+			noun := "copies"
+			if n == 1 {
+				noun = "copy"
+			}
+			hint += desc(". ") + lipgloss.NewStyle().Foreground(styles.Warning).Render(fmt.Sprintf("%d conflicted %s", n, noun)) +
+				desc(", run ") + key("doitdoit doctor conflicts")
+		}
+		if m.filter != "" {
+			hint += desc(". Showing ") + styles.TagStyle.Render(m.filter) + desc(", ") + key("esc") + desc(" to clear")
+		}
+		return styles.HelpStyle.Render(hint)
 	}
 
 	helpItems := m.footerHelpItems()
@@ -347,6 +469,21 @@ func (m Model) footerHelpItems() []helpItem {
 		return m.moveDestinationHelpItems()
 	case SettingMoveDate:
 		return []helpItem{{"enter", "move"}, {"esc", "back"}}
+	case Editing:
+		return []helpItem{{"enter", "save"}, {"esc", "cancel"}}
+	case ViewingDetails:
+		if m.history {
+			return []helpItem{{"esc", "close"}}
+		}
+		return []helpItem{{"n", "edit notes"}, {"esc", "close"}}
+	case SettingRepeat, SettingDeadline:
+		return []helpItem{{"enter", "save"}, {"esc", "cancel"}}
+	case Filtering:
+		return []helpItem{{"enter", "filter"}, {"esc", "cancel"}}
+	case Searching:
+		return []helpItem{{"↑/↓", "choose"}, {"enter", "jump"}, {"esc", "close"}}
+	case ResolvingConflicts:
+		return []helpItem{{"enter", "keep"}, {"o", "use other"}, {"esc", "later"}}
 	default:
 		return nil
 	}
@@ -397,8 +534,8 @@ func (m Model) brandBounds() (x, y, width int, ok bool) {
 
 	x = styles.AppStyle.GetMarginLeft() + styles.HelpStyle.GetMarginLeft()
 	y = styles.AppStyle.GetMarginTop() + lipgloss.Height(columns) + styles.HelpStyle.GetMarginTop()
-	if errView := m.errorView(); errView != "" {
-		y += lipgloss.Height(errView)
+	if notice := m.noticeView(); notice != "" {
+		y += lipgloss.Height(notice)
 	}
 	if x < 0 || x+brandWidth > m.width || y < 0 || y >= m.height {
 		return 0, 0, 0, false
@@ -432,36 +569,48 @@ func (m Model) helpItems() []helpItem {
 		navigation = "↑/↓ / k/j"
 		viewToggle = "main view"
 	}
+	if m.history {
+		return []helpItem{
+			{navigation, "navigate"},
+			{"space / enter", "reopen on Today"},
+			{"c", "copy to Today"},
+			{"i", "task details"},
+			{"/", "search all tasks"},
+			{"#", "filter by tag/project"},
+			{"y", "copy task"},
+			{"u", "undo"},
+			{"ctrl+r", "redo"},
+			{"H / esc", "back to Today"},
+			{"q / ctrl+c", "quit"},
+		}
+	}
 	return []helpItem{
 		{navigation, "navigate"},
 		{"a", "add task"},
 		{"space / enter", "toggle task"},
+		{"e", "edit task"},
+		{"i", "task details"},
+		{"r", "repeat task"},
+		{"D", "set deadline"},
+		{"/", "search all tasks"},
+		{"#", "filter by tag/project"},
 		{"d", "delete task"},
 		{"y", "copy task"},
 		{"m", "move task"},
 		{"J / K", "reorder task"},
 		{".", "repeat move"},
-		{"u", "undo move"},
+		{"u", "undo"},
+		{"ctrl+r", "redo"},
 		{"f", viewToggle},
+		{"H", "history"},
+		{"C", "review sync conflicts"},
 		{"q / ctrl+c", "quit"},
 	}
 }
 
 func (m Model) helpModalView() string {
-	modalStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(styles.Highlight).
-		Padding(1, 2)
-
-	modalWidth := 64
-	if m.width > 0 && modalWidth > m.width-4 {
-		modalWidth = m.width - 4
-	}
-	minimumModalWidth := modalStyle.GetHorizontalFrameSize() + 1
-	if modalWidth < minimumModalWidth {
-		modalWidth = minimumModalWidth
-	}
-	innerWidth := modalWidth - modalStyle.GetHorizontalFrameSize()
+	frame := m.modal(64, styles.Highlight)
+	innerWidth := frame.innerWidth()
 
 	renderItem := func(item helpItem, width int) string {
 		itemGap := 2
@@ -505,17 +654,50 @@ func (m Model) helpModalView() string {
 	}
 
 	closeHint := lipgloss.NewStyle().Foreground(styles.Subtle).Render("Press Esc to close")
-	body := lipgloss.JoinVertical(lipgloss.Left,
-		styles.FocusedTitleStyle.Render("Keyboard shortcuts"),
+	return frame.renderModal("Keyboard shortcuts", lipgloss.JoinVertical(lipgloss.Left,
 		shortcuts,
 		"",
 		closeHint,
-	)
-	return modalStyle.Width(modalWidth).Render(body)
+	))
 }
 
 func (m Model) renderHelpOverlay(background string) string {
-	modal := m.helpModalView()
+	return m.renderOverlay(background, m.helpModalView())
+}
+
+// modalFrame is the bordered box every modal is drawn in.
+type modalFrame struct {
+	style lipgloss.Style
+	width int
+}
+
+// modal returns a frame width columns wide, narrowed to fit the terminal.
+func (m Model) modal(width int, border color.Color) modalFrame {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Padding(1, 2)
+	if m.width > 0 && width > m.width-4 {
+		width = m.width - 4
+	}
+	return modalFrame{style: style, width: max(width, style.GetHorizontalFrameSize()+1)}
+}
+
+// innerWidth is the room left for content inside the border and padding.
+func (f modalFrame) innerWidth() int {
+	return f.width - f.style.GetHorizontalFrameSize()
+}
+
+// renderModal draws title above body inside the frame.
+func (f modalFrame) renderModal(title, body string) string {
+	return f.style.Width(f.width).Render(lipgloss.JoinVertical(lipgloss.Left,
+		styles.FocusedTitleStyle.Width(f.innerWidth()).Render(title),
+		body,
+	))
+}
+
+// renderOverlay centres a modal over the rendered app.
+func (m Model) renderOverlay(background, modal string) string {
 	if m.width <= 0 || m.height <= 0 {
 		return modal
 	}
@@ -530,9 +712,14 @@ func (m Model) renderHelpOverlay(background string) string {
 		Render()
 }
 
-func (m Model) errorView() string {
-	if m.Err == nil {
-		return ""
+// noticeView shows the current error or, failing that, the one-shot status
+// message above the footer.
+func (m Model) noticeView() string {
+	if m.Err != nil {
+		return lipgloss.NewStyle().Foreground(styles.Warning).Render(fmt.Sprintf("Error: %v", m.Err))
+	}
+	if m.status != "" {
+		return lipgloss.NewStyle().Foreground(styles.Special).Render(m.status)
 	}
-	return lipgloss.NewStyle().Foreground(styles.Warning).Render(fmt.Sprintf("Error: %v", m.Err))
+	return ""
 }
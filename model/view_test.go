@@ -13,6 +13,7 @@ import (
 func TestFooterWordmarkOnlyAnimatesWhenClicked(t *testing.T) {
 	today := "2026-08-20"
 	m := Model{
+		Calendar:    testCalendar(),
 		Data:        TodoData{today: {{ID: "1", Title: "Task"}}},
 		VisibleDays: 1,
 		State:       Browsing,
@@ -76,6 +77,7 @@ func TestColumnsFillAvailableWidthAndHaveEqualHeight(t *testing.T) {
 		"2026-08-21",
 	}
 	m := Model{
+		Calendar: testCalendar(),
 		Data: TodoData{
 			keys[0]: {{ID: "1", Title: strings.Repeat("A long task that wraps ", 4)}},
 			keys[1]: {
@@ -122,6 +124,7 @@ func TestHelpModalRendersInsideTerminal(t *testing.T) {
 	today := "2026-08-20"
 	for _, width := range []int{80, 48} {
 		m := Model{
+			Calendar:    testCalendar(),
 			Data:        TodoData{today: {{ID: "1", Title: "Task"}}},
 			VisibleDays: 1,
 			State:       Browsing,
@@ -145,7 +148,7 @@ func TestHelpModalRendersInsideTerminal(t *testing.T) {
 }
 
 func TestHelpModalSeparatesKeysAndExplainsHowToClose(t *testing.T) {
-	m := Model{State: Browsing, width: 80, height: 24}
+	m := Model{Calendar: testCalendar(), State: Browsing, width: 80, height: 24}
 	modal := ansi.Strip(m.helpModalView())
 
 	if !strings.Contains(modal, "Press Esc to close") {
@@ -169,9 +172,10 @@ func TestHelpModalSeparatesKeysAndExplainsHowToClose(t *testing.T) {
 
 func TestHelpModalIsCenteredOverBackground(t *testing.T) {
 	m := Model{
-		State:  Browsing,
-		width:  80,
-		height: 24,
+		Calendar: testCalendar(),
+		State:    Browsing,
+		width:    80,
+		height:   24,
 	}
 	background := strings.Join([]string{
 		"tasks remain visible",
@@ -15,7 +15,7 @@ const (
 )
 
 func TestColumnGroupsStacksWeekend(t *testing.T) {
-	m := Model{VisibleDays: 4}
+	m := Model{Calendar: testCalendar(), VisibleDays: 4}
 	keys := []string{friday, saturday, sunday, monday}
 
 	got := m.columnGroups(keys)
@@ -27,7 +27,7 @@ func TestColumnGroupsStacksWeekend(t *testing.T) {
 }
 
 func TestColumnGroupsLoneSaturday(t *testing.T) {
-	m := Model{VisibleDays: 2}
+	m := Model{Calendar: testCalendar(), VisibleDays: 2}
 	keys := []string{friday, saturday}
 
 	got := m.columnGroups(keys)
@@ -39,7 +39,7 @@ func TestColumnGroupsLoneSaturday(t *testing.T) {
 }
 
 func TestColumnGroupsLoneSunday(t *testing.T) {
-	m := Model{VisibleDays: 2}
+	m := Model{Calendar: testCalendar(), VisibleDays: 2}
 	keys := []string{sunday, monday}
 
 	got := m.columnGroups(keys)
@@ -51,7 +51,7 @@ func TestColumnGroupsLoneSunday(t *testing.T) {
 }
 
 func TestColumnGroupsSingleDayViewNeverStacks(t *testing.T) {
-	m := Model{VisibleDays: 1}
+	m := Model{Calendar: testCalendar(), VisibleDays: 1}
 	keys := []string{saturday}
 
 	got := m.columnGroups(keys)
@@ -63,7 +63,7 @@ func TestColumnGroupsSingleDayViewNeverStacks(t *testing.T) {
 }
 
 func TestColumnGroupsFutureViewSingleColumn(t *testing.T) {
-	m := Model{VisibleDays: 3, ShowFuture: true}
+	m := Model{Calendar: testCalendar(), VisibleDays: 3, ShowFuture: true}
 	keys := []string{"Future"}
 
 	got := m.columnGroups(keys)
@@ -75,7 +75,7 @@ func TestColumnGroupsFutureViewSingleColumn(t *testing.T) {
 }
 
 func TestGroupFocusedMatchesAnyDayInColumn(t *testing.T) {
-	m := Model{VisibleDays: 4, ColIdx: 2} // Sunday focused
+	m := Model{Calendar: testCalendar(), VisibleDays: 4, ColIdx: 2} // Sunday focused
 	weekendColumn := []int{1, 2}
 
 	if !m.groupFocused(weekendColumn) {
@@ -14,6 +14,7 @@ var (
 	Text      color.Color
 	Special   color.Color
 	Warning   color.Color
+	Tag       color.Color
 
 	// Column Styles
 	ColumnStyle        lipgloss.Style
@@ -25,6 +26,9 @@ var (
 	CompletedTaskStyle lipgloss.Style
 	MovingTaskStyle    lipgloss.Style
 
+	// TagStyle colours #tag and +project tokens within task titles.
+	TagStyle lipgloss.Style
+
 	TitleStyle lipgloss.Style
 
 	// FocusedTitleStyle marks the header of the day that currently has focus,
@@ -49,6 +53,7 @@ func Apply(t Theme) {
 	Text = t.Text
 	Special = t.Special
 	Warning = t.Warning
+	Tag = t.Tag
 
 	ColumnStyle = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -78,6 +83,9 @@ func Apply(t Theme) {
 		Bold(true).
 		Padding(0, 1)
 
+	TagStyle = lipgloss.NewStyle().
+		Foreground(Tag)
+
 	TitleStyle = lipgloss.NewStyle().
 		Foreground(Special).
 		Bold(true).
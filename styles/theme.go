@@ -24,21 +24,27 @@ type Theme struct {
 	Warning   color.Color // errors
 	MovingFg  color.Color // task being moved (foreground)
 	MovingBg  color.Color // task being moved (background)
+	Tag       color.Color // #tags and +projects in task titles
+	// Background is the page colour for exported HTML. The TUI leaves the
+	// terminal's own background alone.
+	Background color.Color
 }
 
 // DefaultTheme is the original adaptive palette, used when no theme is
 // configured and no Omarchy install is present.
 func DefaultTheme() Theme {
 	return Theme{
-		Text:      compat.AdaptiveColor{Light: lipgloss.Color("#191919"), Dark: lipgloss.Color("#F8F8F2")},
-		Subtle:    compat.AdaptiveColor{Light: lipgloss.Color("#D9DCCF"), Dark: lipgloss.Color("#6272A4")},
-		Border:    compat.AdaptiveColor{Light: lipgloss.Color("#D9DCCF"), Dark: lipgloss.Color("#6272A4")},
-		Highlight: compat.AdaptiveColor{Light: lipgloss.Color("#874BFD"), Dark: lipgloss.Color("#FF79C6")},
-		Key:       compat.AdaptiveColor{Light: lipgloss.Color("#9B9B9B"), Dark: lipgloss.Color("#BD93F9")},
-		Special:   compat.AdaptiveColor{Light: lipgloss.Color("#43BF6D"), Dark: lipgloss.Color("#50FA7B")},
-		Warning:   compat.AdaptiveColor{Light: lipgloss.Color("#F25D94"), Dark: lipgloss.Color("#FF5555")},
-		MovingFg:  lipgloss.Color("#FFFFFF"),
-		MovingBg:  lipgloss.Color("#FF79C6"),
+		Text:       compat.AdaptiveColor{Light: lipgloss.Color("#191919"), Dark: lipgloss.Color("#F8F8F2")},
+		Subtle:     compat.AdaptiveColor{Light: lipgloss.Color("#D9DCCF"), Dark: lipgloss.Color("#6272A4")},
+		Border:     compat.AdaptiveColor{Light: lipgloss.Color("#D9DCCF"), Dark: lipgloss.Color("#6272A4")},
+		Highlight:  compat.AdaptiveColor{Light: lipgloss.Color("#874BFD"), Dark: lipgloss.Color("#FF79C6")},
+		Key:        compat.AdaptiveColor{Light: lipgloss.Color("#9B9B9B"), Dark: lipgloss.Color("#BD93F9")},
+		Special:    compat.AdaptiveColor{Light: lipgloss.Color("#43BF6D"), Dark: lipgloss.Color("#50FA7B")},
+		Warning:    compat.AdaptiveColor{Light: lipgloss.Color("#F25D94"), Dark: lipgloss.Color("#FF5555")},
+		MovingFg:   lipgloss.Color("#FFFFFF"),
+		MovingBg:   lipgloss.Color("#FF79C6"),
+		Tag:        compat.AdaptiveColor{Light: lipgloss.Color("#0087AF"), Dark: lipgloss.Color("#8BE9FD")},
+		Background: compat.AdaptiveColor{Light: lipgloss.Color("#FFFFFF"), Dark: lipgloss.Color("#282A36")},
 	}
 }
 
@@ -86,16 +92,27 @@ func ThemeFromPalette(palette map[string]string) (Theme, error) {
 	if subtle == "" {
 		subtle = palette["muted"]
 	}
+	// Labels use cyan where the palette has one, otherwise its blue, and
+	// finally the key-hint magenta.
+	tag := palette["cyan"]
+	if tag == "" {
+		tag = palette["blue"]
+	}
+	if tag == "" {
+		tag = palette["magenta"]
+	}
 	return Theme{
-		Text:      lipgloss.Color(palette["foreground"]),
-		Subtle:    lipgloss.Color(subtle),
-		Border:    lipgloss.Color(palette["muted"]),
-		Highlight: lipgloss.Color(palette["accent"]),
-		Key:       lipgloss.Color(palette["magenta"]),
-		Special:   lipgloss.Color(palette["green"]),
-		Warning:   lipgloss.Color(palette["red"]),
-		MovingFg:  lipgloss.Color(palette["background"]),
-		MovingBg:  lipgloss.Color(palette["accent"]),
+		Text:       lipgloss.Color(palette["foreground"]),
+		Subtle:     lipgloss.Color(subtle),
+		Border:     lipgloss.Color(palette["muted"]),
+		Highlight:  lipgloss.Color(palette["accent"]),
+		Key:        lipgloss.Color(palette["magenta"]),
+		Special:    lipgloss.Color(palette["green"]),
+		Warning:    lipgloss.Color(palette["red"]),
+		MovingFg:   lipgloss.Color(palette["background"]),
+		MovingBg:   lipgloss.Color(palette["accent"]),
+		Tag:        lipgloss.Color(tag),
+		Background: lipgloss.Color(palette["background"]),
 	}, nil
 }
 
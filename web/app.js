@@ -16,6 +16,8 @@
   const FILE_PATH = CFG.dropboxFilePath || "/doitdoit.json";
   const VISIBLE_DAYS = Math.max(1, CFG.visibleDays || 5);
   const PRUNE_AFTER_DAYS = CFG.pruneAfterDays || 5;
+  const DAY_START_MINUTES = parseDayStart(CFG.dayStartsAt);
+  const HOME_ZONE = CFG.timeZone || "";
   const REDIRECT_URI = window.location.origin + window.location.pathname;
 
   // ── DOM refs ───────────────────────────────────────────────────────
@@ -37,6 +39,7 @@
   const editDialog = $("edit-dialog");
   const editForm = $("edit-form");
   const editTitle = $("edit-title");
+  const editNotes = $("edit-notes");
   const editSchedule = $("edit-schedule");
   const editDate = $("edit-date");
   const editDateLabel = $("edit-date-label");
@@ -50,6 +53,7 @@
   // ── State ──────────────────────────────────────────────────────────
   const state = {
     data: null,         // TodoData = { "YYYY-MM-DD" | "Future": Task[] }
+    envelope: null,     // the file's other top-level fields, kept on save
     rev: null,          // dropbox file revision (for conflict detection)
     accessToken: null,
     refreshToken: null,
@@ -229,7 +233,7 @@
     });
     if (r.status === 409) {
       // path/not_found — file doesn't exist yet. Start empty.
-      return { data: {}, rev: null };
+      return { data: {}, envelope: { version: SCHEMA_VERSION }, rev: null };
     }
     if (r.status === 401) {
       await refreshAccessToken();
@@ -241,17 +245,19 @@
     }
     const meta = JSON.parse(r.headers.get("Dropbox-API-Result") || "{}");
     const text = await r.text();
-    let data = {};
+    let doc = {};
     if (text.trim()) {
-      try { data = JSON.parse(text); }
+      try { doc = JSON.parse(text); }
       catch { throw new Error("dropbox file is not valid JSON"); }
     }
-    return { data, rev: meta.rev || null };
+    const { data, envelope } = unwrapDataFile(doc);
+    return { data, envelope, rev: meta.rev || null };
   }
 
   async function dbxUpload(data, rev) {
     await ensureToken();
-    const body = JSON.stringify(data, null, 2);
+    const doc = Object.assign({}, state.envelope, { version: SCHEMA_VERSION, tasks: data });
+    const body = JSON.stringify(doc, null, 2);
     const args = rev
       ? { path: FILE_PATH, mode: { ".tag": "update", update: rev }, mute: true, autorename: false }
       : { path: FILE_PATH, mode: "overwrite", mute: true, autorename: false };
@@ -281,8 +287,62 @@
     return meta.rev;
   }
 
+  // ── Data file schema — mirrors model/schema.go ────────────────────
+  // The file is an envelope {"version": N, "tasks": {...}}. Files written
+  // before versioning are a bare task map (version 0) and are upgraded on the
+  // next save. A newer version is refused outright so this page can never
+  // overwrite fields it does not understand.
+  const SCHEMA_VERSION = 1;
+
+  function unwrapDataFile(doc) {
+    if (doc && typeof doc.version === "number") {
+      if (doc.version > SCHEMA_VERSION) {
+        throw Object.assign(
+          new Error("file uses a newer format (schema " + doc.version + "); update this app"),
+          { newerSchema: true },
+        );
+      }
+      const { tasks, ...envelope } = doc;
+      return { data: tasks || {}, envelope };
+    }
+    return { data: doc || {}, envelope: { version: SCHEMA_VERSION } };
+  }
+
   // ── Domain logic — ported from model/task.go ──────────────────────
-  function todayStr(d = new Date()) {
+  // config/config.go — ParseDayStart; anything unreadable means midnight
+  function parseDayStart(value) {
+    const m = /^(\d{1,2}):(\d{2})$/.exec(value || "");
+    if (!m || +m[1] > 23 || +m[2] > 59) return 0;
+    return +m[1] * 60 + +m[2];
+  }
+  // Day decisions read "now" moved back by the day start, as the CLI's
+  // `day_starts_at` setting does, so 00:30 still counts as the day before
+  // when days start at 04:00. Dates are handled through their local fields,
+  // so when a home zone is configured "now" is first shifted to show that
+  // zone's wall clock, as the CLI's `time_zone` setting does. Every device
+  // then agrees on the day.
+  function homeNow() {
+    const now = zoneNow();
+    now.setMinutes(now.getMinutes() - DAY_START_MINUTES);
+    return now;
+  }
+  function zoneNow() {
+    const now = new Date();
+    if (!HOME_ZONE) return now;
+    try {
+      const parts = {};
+      const fmt = new Intl.DateTimeFormat("en-US", {
+        timeZone: HOME_ZONE, hourCycle: "h23",
+        year: "numeric", month: "numeric", day: "numeric",
+        hour: "numeric", minute: "numeric", second: "numeric",
+      });
+      for (const p of fmt.formatToParts(now)) parts[p.type] = +p.value;
+      return new Date(parts.year, parts.month - 1, parts.day, parts.hour, parts.minute, parts.second);
+    } catch {
+      return now;
+    }
+  }
+  function todayStr(d = homeNow()) {
     const y = d.getFullYear();
     const m = String(d.getMonth() + 1).padStart(2, "0");
     const day = String(d.getDate()).padStart(2, "0");
@@ -303,21 +363,104 @@
     return new Date(d.getFullYear(), d.getMonth(), d.getDate());
   }
 
+  // #tag and +project labels — ported from model/labels.go. The parsed
+  // fields are stored explicitly so scripts reading the file need not
+  // re-parse titles; keep them in step whenever a title changes.
+  function parseLabels(title) {
+    const tags = [];
+    const projects = [];
+    for (const word of String(title || "").split(/\s+/)) {
+      if (word[0] !== "#" && word[0] !== "+") continue;
+      // trailing punctuation other than _ and - is not part of the label
+      const name = word.slice(1).replace(/(?:(?![_-])\p{P})+$/u, "").toLowerCase();
+      if (!/^\p{L}/u.test(name)) continue;
+      const list = word[0] === "#" ? tags : projects;
+      if (!list.includes(name)) list.push(name);
+    }
+    return { tags, projects };
+  }
+  function syncLabels(task) {
+    const { tags, projects } = parseLabels(task.title);
+    if (tags.length) task.tags = tags;
+    else delete task.tags;
+    if (projects.length) task.projects = projects;
+    else delete task.projects;
+  }
+
+  // Recurrence rules — ported from model/recurrence.go. The file stores the
+  // canonical text form; anything unrecognised is treated as non-repeating.
+  const WEEKDAYS = ["sun", "mon", "tue", "wed", "thu", "fri", "sat"];
+  function parseRepeat(rule) {
+    const words = String(rule || "").toLowerCase().replace(/,/g, " ").split(/\s+/).filter(Boolean);
+    const phrase = words.join(" ");
+    if (phrase === "daily" || phrase === "every day") return { kind: "daily" };
+    if (phrase === "weekdays" || phrase === "every weekday") return { kind: "weekdays" };
+    let m = /^every (\d+) days?$/.exec(phrase);
+    if (m && +m[1] >= 1) return +m[1] === 1 ? { kind: "daily" } : { kind: "every", interval: +m[1] };
+    if (words[0] === "weekly" && words[1] === "on" && words.length > 2) {
+      const days = words.slice(2).filter((w) => w !== "and").map((w) => WEEKDAYS.indexOf(w.slice(0, 3)));
+      if (days.length && days.every((d) => d >= 0)) return { kind: "weekly", days };
+      return null;
+    }
+    m = /^monthly on (?:the )?(\d+)(?:st|nd|rd|th)?$/.exec(phrase);
+    if (m && +m[1] >= 1 && +m[1] <= 31) return { kind: "monthly", day: +m[1] };
+    return null;
+  }
+  function nextRepeat(rule, after) {
+    after = startOfDay(after);
+    if (rule.kind === "every") return addDays(after, rule.interval);
+    if (rule.kind === "monthly") {
+      for (let months = 0; ; months++) {
+        const lastDay = new Date(after.getFullYear(), after.getMonth() + months + 1, 0).getDate();
+        const date = new Date(after.getFullYear(), after.getMonth() + months, Math.min(rule.day, lastDay));
+        if (date > after) return date;
+      }
+    }
+    for (let date = addDays(after, 1); ; date = addDays(date, 1)) {
+      const wd = date.getDay();
+      if (rule.kind === "daily") return date;
+      if (rule.kind === "weekdays" && wd !== 0 && wd !== 6) return date;
+      if (rule.kind === "weekly" && rule.days.includes(wd)) return date;
+    }
+  }
+  // Counts on from the later of the task's own date and today, and hands
+  // the rule to the new occurrence so re-completing cannot duplicate it.
+  function scheduleNextOccurrence(dayKey, task) {
+    const rule = task.repeat && parseRepeat(task.repeat);
+    if (!rule) return;
+    let anchor = startOfDay(homeNow());
+    const own = parseDay(task.due_date || (dayKey !== "Future" ? dayKey : ""));
+    if (own && own > anchor) anchor = own;
+    const next = {
+      id: genId(),
+      title: task.title,
+      completed: false,
+      created_at: new Date().toISOString(),
+      due_date: todayStr(nextRepeat(rule, anchor)),
+      repeat: task.repeat,
+    };
+    syncLabels(next);
+    if (task.notes) next.notes = task.notes;
+    delete task.repeat;
+    const future = state.data["Future"] || (state.data["Future"] = []);
+    insertBeforeCompleted(future, next);
+  }
+
   function lastVisibleDate() {
-    return startOfDay(addDays(new Date(), VISIBLE_DAYS - 1));
+    return startOfDay(addDays(homeNow(), VISIBLE_DAYS - 1));
   }
 
   function storageTarget(target) {
     if (target.kind === "future") return { key: "Future", due: "" };
 
     let date = target.kind === "tomorrow"
-      ? addDays(new Date(), 1)
+      ? addDays(homeNow(), 1)
       : target.kind === "custom"
         ? parseDay(target.date)
-        : new Date();
+        : homeNow();
     if (!date) return { error: "choose a valid date" };
     date = startOfDay(date);
-    const today = startOfDay(new Date());
+    const today = startOfDay(homeNow());
     if (date < today) date = today;
     const due = todayStr(date);
     return {
@@ -330,14 +473,14 @@
     if (dayKey === "Future" && !task.due_date) return { kind: "future", date: "" };
     const due = task.due_date || dayKey;
     if (due === todayStr()) return { kind: "today", date: due };
-    if (due === todayStr(addDays(new Date(), 1))) return { kind: "tomorrow", date: due };
+    if (due === todayStr(addDays(homeNow(), 1))) return { kind: "tomorrow", date: due };
     return { kind: "custom", date: due };
   }
 
   // model/task.go:132 — rollOverIncompleteTasks
   function rollOverIncompleteTasks(data) {
     const today = todayStr();
-    const todayDate = startOfDay(new Date());
+    const todayDate = startOfDay(homeNow());
     const toRoll = [];
     const datesToRemove = [];
     let changed = false;
@@ -351,6 +494,10 @@
         for (const t of data[dateStr]) {
           if (!t.completed) {
             t.due_date = today;
+            // count the rollover and remember the original plan, as the Go
+            // side does, so staleness holds whichever device rolls first
+            if (!t.first_scheduled) t.first_scheduled = dateStr;
+            t.rollovers = (t.rollovers || 0) + 1;
             toRoll.push(t);
           } else {
             remaining.push(t);
@@ -372,7 +519,7 @@
 
   // model/task.go:241 — pruneOldTasks
   function pruneOldTasks(data) {
-    const cutoff = todayStr(addDays(new Date(), -PRUNE_AFTER_DAYS));
+    const cutoff = todayStr(addDays(homeNow(), -PRUNE_AFTER_DAYS));
     let changed = false;
     for (const k of Object.keys(data)) {
       if (k === "Future") {
@@ -390,7 +537,7 @@
   function distributeFutureTasks(data, visibleDays) {
     const future = data["Future"] || [];
     if (!future.length) return;
-    const today = startOfDay(new Date());
+    const today = startOfDay(homeNow());
     const lastVisible = addDays(today, visibleDays - 1);
     const todayKey = todayStr(today);
     const remain = [];
@@ -412,7 +559,7 @@
   const DOW = ["sun", "mon", "tue", "wed", "thu", "fri", "sat"];
 
   function buildView(data) {
-    const today = new Date();
+    const today = homeNow();
     const todayKey = todayStr(today);
     const days = [];
 
@@ -518,6 +665,7 @@
   }
 
   function addTask(rawInput, selectedTarget) {
+    if (!state.data) { toast("tasks are not loaded", "err"); return; }
     const parsed = parseAddInput(rawInput, selectedTarget);
     if (parsed.error) { toast(parsed.error, "err"); return; }
     const t = {
@@ -526,6 +674,7 @@
       completed: false,
       created_at: new Date().toISOString(),
     };
+    syncLabels(t);
     if (parsed.due) t.due_date = parsed.due;
     if (!state.data[parsed.key]) state.data[parsed.key] = [];
     insertBeforeCompleted(state.data[parsed.key], t);
@@ -552,8 +701,10 @@
     // Reorder to match the CLI: completed tasks sink to the bottom of the
     // day, uncompleted tasks move back above the completed block.
     f.list.splice(f.idx, 1);
-    if (f.task.completed) f.list.push(f.task);
-    else insertBeforeCompleted(f.list, f.task);
+    if (f.task.completed) {
+      f.list.push(f.task);
+      scheduleNextOccurrence(dayKey, f.task);
+    } else insertBeforeCompleted(f.list, f.task);
     render({ preserveScroll: true });
     queueSave();
   }
@@ -580,6 +731,8 @@
 
     if (destinationKey === "Future") delete task.due_date;
     else task.due_date = destinationKey;
+    // moving to another day is a decision, so the rollover count restarts
+    if (destinationKey !== dayKey) delete task.rollovers;
     const targetList = state.data[destinationKey] || (state.data[destinationKey] = []);
     const index = Math.max(0, Math.min(destinationIndex, targetList.length));
     targetList.splice(index, 0, task);
@@ -596,6 +749,7 @@
   }
 
   async function doSave() {
+    if (!state.data) return;
     if (state.interactionActive) { saveTimer = setTimeout(doSave, 400); return; }
     if (state.saving) { saveTimer = setTimeout(doSave, 400); return; }
     state.saving = true;
@@ -623,9 +777,10 @@
     if (state.interactionActive) return;
     setSync("syncing");
     try {
-      const { data, rev } = await dbxDownload();
+      const { data, envelope, rev } = await dbxDownload();
       const before = state.data ? JSON.stringify(state.data) : null;
       state.data = data;
+      state.envelope = envelope;
       state.rev = rev;
       const r1 = rollOverIncompleteTasks(state.data);
       const r2 = pruneOldTasks(state.data);
@@ -646,6 +801,8 @@
       }
     } catch (err) {
       console.error(err);
+      // Drop what we hold so no later save can downgrade a newer file.
+      if (err.newerSchema) state.data = null;
       toast("load failed: " + err.message, "err");
       setSync("error");
     }
@@ -686,6 +843,7 @@
     state.editing = { dayKey, id };
     state.interactionActive = true;
     editTitle.value = found.task.title;
+    editNotes.value = found.task.notes || "";
     editTarget = targetForTask(dayKey, found.task);
     paintSchedule(editSchedule, "data-edit-schedule", editTarget, editDate, editDateLabel);
     if (typeof editDialog.showModal === "function") editDialog.showModal();
@@ -721,10 +879,16 @@
     if (!found) { closeEditor(); return; }
     const task = found.task;
     task.title = title;
+    syncLabels(task);
+    // notes are optional; match the Go side's omitempty and trailing trim
+    const notes = editNotes.value.replace(/\s+$/, "");
+    if (notes) task.notes = notes;
+    else delete task.notes;
     if (destination.due) task.due_date = destination.due;
     else delete task.due_date;
 
     if (destination.key !== dayKey) {
+      delete task.rollovers;
       found.list.splice(found.idx, 1);
       if (found.list.length === 0 && dayKey !== "Future") delete state.data[dayKey];
       const targetList = state.data[destination.key] || (state.data[destination.key] = []);
@@ -26,4 +26,14 @@ window.DOITDOIT_CONFIG = {
 
   // Mirrors model/task.go:14 — keep in sync with CLI.
   pruneAfterDays: 5,
+
+  // When a new day begins ("HH:MM"). Set it to the CLI's `day_starts_at` so
+  // this device keeps "today" as late as the CLI does; leave it empty for
+  // midnight.
+  dayStartsAt: "",
+
+  // Home time zone (IANA name, e.g. "Europe/London"). Set it to the CLI's
+  // `time_zone` so this device agrees on "today" while you travel; leave it
+  // empty to follow the device's zone.
+  timeZone: "",
 };